@@ -1,10 +1,24 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// AclOperations are the operations a rule can allow or deny.
+var AclOperations = []string{"all", "read", "write", "create", "delete", "alter", "describe",
+	"cluster_action", "describe_configs", "alter_configs", "idempotent_write", "create_tokens", "describe_tokens"}
+
+// AclResourceTypes are the kinds of resources a rule can apply to.
+var AclResourceTypes = []string{"cluster", "topic", "group", "transactional_id", "delegation_token"}
+
+// AclPermissionTypes are the permission types of a rule.
+var AclPermissionTypes = []string{"allow", "deny"}
+
 type AclRule struct {
 	Id                  int64  `json:"id"`
 	User                string `json:"name"`
@@ -13,33 +27,40 @@ type AclRule struct {
 	ResourcePattern     string `json:"resource_pattern"`
 	CreatedAt           string `json:"created_at"`
 	ResourcePatternType string `json:"resource_pattern_type"`
+	// PermissionType is allow or deny, rules without one allow.
+	PermissionType string `json:"permission_type,omitempty"`
+	// Host is the address the rule applies to, rules without one apply to
+	// all hosts.
+	Host string `json:"host,omitempty"`
 }
 
+// Same reports whether r and other are rules for the same user that allow
+// or deny the same thing.
 func (r *AclRule) Same(other *AclRule) bool {
-	return r.User == other.User &&
-		r.Operation == other.Operation && r.Resource == other.Resource &&
-		r.ResourcePattern == other.ResourcePattern &&
-		r.ResourcePatternType == r.ResourcePatternType
+	return r.User == other.User && r.key() == other.key()
 }
 
-func (api *API) readAclRules(instanceId int64) ([]AclRule, error) {
+func (api *API) readAclRules(ctx context.Context, instanceId int64) ([]AclRule, error) {
 	var (
 		data   []AclRule
 		failed APIError
 	)
 	path := fmt.Sprintf("/api/instances/%d/acls", instanceId)
-	resp, err := api.client.New().Get(path).Receive(&data, &failed)
+	resp, err := api.request(ctx).Get(path).Receive(&data, &failed)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("instance with id %d %w", instanceId, ErrNotFound)
+	}
 	if resp.StatusCode != 200 {
 		return nil, failed
 	}
 	return data, nil
 }
 
-func (api *API) ReadAclRule(instanceId int64, id int64) (*AclRule, error) {
-	data, err := api.readAclRules(instanceId)
+func (api *API) ReadAclRule(ctx context.Context, instanceId int64, id int64) (*AclRule, error) {
+	data, err := api.readAclRules(ctx, instanceId)
 	if err != nil {
 		return nil, err
 	}
@@ -48,43 +69,163 @@ func (api *API) ReadAclRule(instanceId int64, id int64) (*AclRule, error) {
 			return &v, nil
 		}
 	}
-	return nil, fmt.Errorf("No rule found with id=%d", id)
+	return nil, fmt.Errorf("rule with id %d %w", id, ErrNotFound)
+}
+
+// FindAclRule returns the rule of the user that allows or denies the same
+// as rule, whatever its id.
+func (api *API) FindAclRule(ctx context.Context, instanceId int64, rule AclRule) (*AclRule, error) {
+	data, err := api.ListAclRules(ctx, instanceId, rule.User)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range data {
+		if v.Same(&rule) {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("rule %s:%s:%s:%s:%s for %s %w", rule.Resource, rule.ResourcePattern,
+		rule.ResourcePatternType, rule.Operation, rule.PermissionType, rule.User, ErrNotFound)
+}
+
+func (api *API) CreateAclRule(ctx context.Context, instanceId int64, user string, rule AclRule) (int64, error) {
+	if err := api.createAclRules(ctx, instanceId, user, []AclRule{rule}); err != nil {
+		return -1, err
+	}
+	data, err := api.readAclRules(ctx, instanceId)
+	if err != nil {
+		return -1, err
+	}
+	rule.User = user
+	for _, v := range data {
+		if v.Same(&rule) {
+			return v.Id, nil
+		}
+	}
+	return -1, errors.New("Failed to create rule")
 }
 
-func (api *API) CreateAclRule(instanceId int64, user string, rule AclRule) (int64, error) {
+// createAclRules adds rules for the user in one request.
+func (api *API) createAclRules(ctx context.Context, instanceId int64, user string, rules []AclRule) error {
 	var failed APIError
 	path := fmt.Sprintf("/api/instances/%d/acls", instanceId)
 	body := map[string]interface{}{
 		"user":  user,
-		"rules": []AclRule{rule},
+		"rules": rules,
 	}
-	resp, err := api.client.New().Post(path).BodyJSON(body).Receive(nil, &failed)
+	resp, err := api.request(ctx).Post(path).BodyJSON(body).Receive(nil, &failed)
 	if err != nil {
-		return -1, err
+		return err
 	}
 	if resp.StatusCode != 201 {
-		return -1, failed
+		return failed
 	}
-	data, err := api.readAclRules(instanceId)
+	return nil
+}
+
+// ListAclRules returns the ACL rules of the user.
+func (api *API) ListAclRules(ctx context.Context, instanceId int64, user string) ([]AclRule, error) {
+	data, err := api.readAclRules(ctx, instanceId)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
-	rule.User = user
+	rules := []AclRule{}
 	for _, v := range data {
-		if v.Same(&rule) {
-			return v.Id, nil
+		if v.User == user {
+			rules = append(rules, v)
 		}
 	}
-	return -1, errors.New("Failed to create rule")
+	return rules, nil
+}
+
+// SetAclRules makes rules the only ACL rules of the user. Missing rules are
+// added in one request and rules not in the list are deleted one by one,
+// rules that are already in place are left alone.
+func (api *API) SetAclRules(ctx context.Context, instanceId int64, user string, rules []AclRule) error {
+	current, err := api.ListAclRules(ctx, instanceId, user)
+	if err != nil {
+		return err
+	}
+	add, remove := DiffAclRules(current, rules)
+	if len(add) > 0 {
+		tflog.Info(ctx, fmt.Sprintf("Adding %d ACL rules for %s", len(add), user))
+		if err := api.createAclRules(ctx, instanceId, user, add); err != nil {
+			return err
+		}
+	}
+	for _, v := range remove {
+		tflog.Info(ctx, fmt.Sprintf("Deleting ACL rule %d for %s", v.Id, user))
+		err := api.DeleteAclRule(ctx, instanceId, v.Id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return nil
 }
 
-func (api *API) DeleteAclRule(instanceId int64, id int64) error {
+// DiffAclRules returns the rules in wanted that are missing from current,
+// and the rules in current that are not wanted. Duplicates in current are
+// removed. Rules are compared without their user, id and creation time.
+func DiffAclRules(current, wanted []AclRule) (add, remove []AclRule) {
+	keep := make(map[aclKey]bool)
+	for _, v := range wanted {
+		keep[v.key()] = false
+	}
+	for _, v := range current {
+		if kept, ok := keep[v.key()]; ok && !kept {
+			keep[v.key()] = true
+			continue
+		}
+		remove = append(remove, v)
+	}
+	for _, v := range wanted {
+		if !keep[v.key()] {
+			keep[v.key()] = true
+			add = append(add, v)
+		}
+	}
+	return add, remove
+}
+
+// aclKey identifies what an ACL rule allows or denies. The API does not
+// care about the case of its values, and operations and resource types may
+// be written without underscores, as Kafka does.
+type aclKey struct {
+	operation, resource, pattern, patternType, permissionType, host string
+}
+
+func (r *AclRule) key() aclKey {
+	k := aclKey{
+		operation:      normalizeAclName(r.Operation),
+		resource:       normalizeAclName(r.Resource),
+		pattern:        r.ResourcePattern,
+		patternType:    strings.ToLower(r.ResourcePatternType),
+		permissionType: strings.ToLower(r.PermissionType),
+		host:           r.Host,
+	}
+	if k.permissionType == "" {
+		k.permissionType = "allow"
+	}
+	if k.host == "" {
+		k.host = "*"
+	}
+	return k
+}
+
+func normalizeAclName(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
+
+func (api *API) DeleteAclRule(ctx context.Context, instanceId int64, id int64) error {
 	var failed APIError
 	path := fmt.Sprintf("/api/instances/%d/acls/%d", instanceId, id)
-	resp, err := api.client.New().Delete(path).Receive(nil, &failed)
+	resp, err := api.request(ctx).Delete(path).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
+	if resp.StatusCode == 404 {
+		return fmt.Errorf("rule with id %d %w", id, ErrNotFound)
+	}
 	if resp.StatusCode != 200 {
 		return failed
 	}
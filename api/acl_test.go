@@ -0,0 +1,117 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffAclRules(t *testing.T) {
+	read := AclRule{Operation: "read", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "literal"}
+	write := AclRule{Operation: "write", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "literal"}
+	prefixed := AclRule{Operation: "read", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "prefixed"}
+	stored := func(id int64, r AclRule) AclRule {
+		r.Id, r.User, r.CreatedAt = id, "alice", "2023-01-01T00:00:00Z"
+		return r
+	}
+	deny := AclRule{Operation: "read", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "literal", PermissionType: "deny"}
+	host := AclRule{Operation: "read", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "literal", Host: "10.0.0.1"}
+	upper := AclRule{Operation: "READ", Resource: "TOPIC", ResourcePattern: "orders", ResourcePatternType: "LITERAL"}
+
+	tests := []struct {
+		name    string
+		current []AclRule
+		wanted  []AclRule
+		add     []AclRule
+		remove  []AclRule
+	}{
+		{
+			name:    "nothing to do",
+			current: []AclRule{stored(1, read)},
+			wanted:  []AclRule{read},
+		},
+		{
+			name:    "add and remove",
+			current: []AclRule{stored(1, read), stored(2, write)},
+			wanted:  []AclRule{read, prefixed},
+			add:     []AclRule{prefixed},
+			remove:  []AclRule{stored(2, write)},
+		},
+		{
+			name:    "duplicates are removed",
+			current: []AclRule{stored(1, read), stored(2, read)},
+			wanted:  []AclRule{read, read},
+			remove:  []AclRule{stored(2, read)},
+		},
+		{
+			name:    "case is ignored",
+			current: []AclRule{stored(1, upper)},
+			wanted:  []AclRule{read},
+		},
+		{
+			name:    "deny and host are part of the rule",
+			current: []AclRule{stored(1, read), stored(2, deny)},
+			wanted:  []AclRule{read, host},
+			add:     []AclRule{host},
+			remove:  []AclRule{stored(2, deny)},
+		},
+		{
+			name:    "delete all",
+			current: []AclRule{stored(1, read)},
+			remove:  []AclRule{stored(1, read)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, remove := DiffAclRules(tt.current, tt.wanted)
+			if !reflect.DeepEqual(add, tt.add) {
+				t.Errorf("add = %+v, want %+v", add, tt.add)
+			}
+			if !reflect.DeepEqual(remove, tt.remove) {
+				t.Errorf("remove = %+v, want %+v", remove, tt.remove)
+			}
+		})
+	}
+}
+
+func TestAclRuleSame(t *testing.T) {
+	rule := AclRule{User: "alice", Operation: "describe_configs", Resource: "transactional_id", ResourcePattern: "tx", ResourcePatternType: "literal"}
+	tests := []struct {
+		name  string
+		other AclRule
+		want  bool
+	}{
+		{
+			name:  "defaults",
+			other: AclRule{User: "alice", Operation: "describe_configs", Resource: "transactional_id", ResourcePattern: "tx", ResourcePatternType: "literal", PermissionType: "ALLOW", Host: "*"},
+			want:  true,
+		},
+		{
+			name:  "kafka names",
+			other: AclRule{User: "alice", Operation: "DescribeConfigs", Resource: "TransactionalId", ResourcePattern: "tx", ResourcePatternType: "LITERAL"},
+			want:  true,
+		},
+		{
+			name:  "pattern type",
+			other: AclRule{User: "alice", Operation: "describe_configs", Resource: "transactional_id", ResourcePattern: "tx", ResourcePatternType: "prefixed"},
+		},
+		{
+			name:  "permission type",
+			other: AclRule{User: "alice", Operation: "describe_configs", Resource: "transactional_id", ResourcePattern: "tx", ResourcePatternType: "literal", PermissionType: "deny"},
+		},
+		{
+			name:  "host",
+			other: AclRule{User: "alice", Operation: "describe_configs", Resource: "transactional_id", ResourcePattern: "tx", ResourcePatternType: "literal", Host: "10.0.0.1"},
+		},
+		{
+			name:  "user",
+			other: AclRule{User: "bob", Operation: "describe_configs", Resource: "transactional_id", ResourcePattern: "tx", ResourcePatternType: "literal"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rule.Same(&tt.other); got != tt.want {
+				t.Errorf("Same() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,38 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/dghubble/sling"
 )
 
 type Hash map[string]interface{}
 
+// ValueString formats a decoded JSON config value the way Kafka would print it.
+func ValueString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 type API struct {
-	client *sling.Sling
+	client       *sling.Sling
+	doer         sling.Doer
+	retry        *retryTransport
+	instancePoll time.Duration
+	topicPoll    time.Duration
 }
 
 type APIError struct {
@@ -17,6 +40,10 @@ type APIError struct {
 	Messages []map[string]string `json:"errors"`
 }
 
+// ErrNotFound is returned, possibly wrapped, when the requested object does
+// not exist on the server. Check for it with errors.Is.
+var ErrNotFound = errors.New("not found")
+
 func (e APIError) Error() string {
 	if e.Message != "" {
 		return e.Message
@@ -29,14 +56,74 @@ func (e APIError) Error() string {
 	return "unknown error response"
 }
 
-func New(customerBase, customerApiKey string) *API {
-	sling := sling.New().
-		Client(http.DefaultClient).
+// contextDoer binds every request it sends to ctx, so that cancellation and
+// deadlines abort requests that are in flight.
+type contextDoer struct {
+	ctx  context.Context
+	doer sling.Doer
+}
+
+func (d contextDoer) Do(req *http.Request) (*http.Response, error) {
+	return d.doer.Do(req.WithContext(d.ctx))
+}
+
+// Option configures the API client.
+type Option func(*API)
+
+// WithRetries sets how many times a failed idempotent request is retried and
+// the longest time to wait between two attempts.
+func WithRetries(maxRetries int, maxWait time.Duration) Option {
+	return func(api *API) {
+		api.retry.maxRetries = maxRetries
+		api.retry.maxWait = maxWait
+	}
+}
+
+// WithPollInterval sets how often the client checks whether an instance or
+// topic is ready. Mostly useful against a fake server in tests.
+func WithPollInterval(d time.Duration) Option {
+	return func(api *API) {
+		api.instancePoll = d
+		api.topicPoll = d
+	}
+}
+
+func New(customerBase, customerApiKey string, opts ...Option) *API {
+	api := &API{
+		retry: &retryTransport{
+			next:       http.DefaultTransport,
+			maxRetries: DefaultMaxRetries,
+			maxWait:    DefaultRetryMaxWait,
+		},
+		instancePoll: 10 * time.Second,
+		topicPoll:    5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(api)
+	}
+	client := &http.Client{Transport: api.retry}
+	api.client = sling.New().
+		Client(client).
 		Base(customerBase).
 		SetBasicAuth("", customerApiKey).
 		Set("User-Agent", "terraform")
+	api.doer = client
+	return api
+}
+
+// request returns a new request builder bound to ctx.
+func (api *API) request(ctx context.Context) *sling.Sling {
+	return api.client.New().Doer(contextDoer{ctx: ctx, doer: api.doer})
+}
 
-	return &API{
-		client: sling,
+// sleep waits for d, or returns the context error if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
 	}
 }
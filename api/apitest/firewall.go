@@ -0,0 +1,83 @@
+package apitest
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"terraform-provider-cloudkarafka/api"
+)
+
+// defaultFirewall opens all services to any address, as on new instances.
+func defaultFirewall() []api.FirewallRule {
+	return []api.FirewallRule{{
+		IP:          "0.0.0.0/0",
+		Services:    append([]string(nil), api.FirewallServices...),
+		Ports:       []int64{},
+		Description: "Default",
+	}}
+}
+
+// SetFirewall replaces the firewall rules of an existing instance, as a
+// change made outside Terraform would.
+func (s *Server) SetFirewall(instanceID int64, rules []api.FirewallRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mustInstance(instanceID).firewall = append([]api.FirewallRule{}, rules...)
+}
+
+// Firewall returns a copy of the firewall rules of an instance.
+func (s *Server) Firewall(instanceID int64) []api.FirewallRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]api.FirewallRule{}, s.mustInstance(instanceID).firewall...)
+}
+
+func (i *instance) serveFirewall(w http.ResponseWriter, r *http.Request, readyAfter int) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, append([]api.FirewallRule{}, i.firewall...))
+	case http.MethodPut:
+		var rules []api.FirewallRule
+		if !readJSON(w, r, &rules) {
+			return
+		}
+		for _, rule := range rules {
+			if err := validFirewallRule(rule); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		i.firewall, i.firewallPending = rules, readyAfter
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		i.firewall, i.firewallPending = defaultFirewall(), readyAfter
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func validFirewallRule(rule api.FirewallRule) error {
+	if _, _, err := net.ParseCIDR(rule.IP); err != nil {
+		return fmt.Errorf("invalid ip %q, must be a CIDR block", rule.IP)
+	}
+	if len(rule.Services) == 0 && len(rule.Ports) == 0 {
+		return fmt.Errorf("rule for %s opens no services or ports", rule.IP)
+	}
+	for _, s := range rule.Services {
+		known := false
+		for _, k := range api.FirewallServices {
+			known = known || s == k
+		}
+		if !known {
+			return fmt.Errorf("unknown service %s", s)
+		}
+	}
+	for _, p := range rule.Ports {
+		if p < 1 || p > 65535 {
+			return fmt.Errorf("invalid port %d", p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package apitest
+
+import (
+	"net/http"
+
+	"terraform-provider-cloudkarafka/api"
+)
+
+// SetQuota stores a quota on an existing instance, replacing the quota of
+// the same user and client ID.
+func (s *Server) SetQuota(instanceID int64, q api.Quota) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.mustInstance(instanceID)
+	if n := i.findQuota(q.User, q.ClientId); n >= 0 {
+		i.quotas[n] = q
+		return
+	}
+	i.quotas = append(i.quotas, q)
+}
+
+// Quota returns a copy of the quota of user and clientID.
+func (s *Server) Quota(instanceID int64, user, clientID string) (api.Quota, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.mustInstance(instanceID)
+	if n := i.findQuota(user, clientID); n >= 0 {
+		return i.quotas[n], true
+	}
+	return api.Quota{}, false
+}
+
+func (i *instance) findQuota(user, clientID string) int {
+	for n, q := range i.quotas {
+		if q.User == user && q.ClientId == clientID {
+			return n
+		}
+	}
+	return -1
+}
+
+// validQuota returns why q cannot be stored, or an empty string.
+func validQuota(q api.Quota) string {
+	switch {
+	case q.User == "" && q.ClientId == "":
+		return "user or client_id is required"
+	case q.ProducerByteRate == nil && q.ConsumerByteRate == nil && q.RequestPercentage == nil:
+		return "at least one limit is required"
+	case q.ProducerByteRate != nil && *q.ProducerByteRate <= 0,
+		q.ConsumerByteRate != nil && *q.ConsumerByteRate <= 0,
+		q.RequestPercentage != nil && *q.RequestPercentage <= 0:
+		return "limits must be positive"
+	}
+	return ""
+}
+
+func (i *instance) serveQuotas(w http.ResponseWriter, r *http.Request) {
+	user, clientID := r.URL.Query().Get("user"), r.URL.Query().Get("client_id")
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, append([]api.Quota{}, i.quotas...))
+	case http.MethodPost:
+		var req api.Quota
+		if !readJSON(w, r, &req) {
+			return
+		}
+		if msg := validQuota(req); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+		if i.findQuota(req.User, req.ClientId) >= 0 {
+			writeError(w, http.StatusConflict, "quota already exists")
+			return
+		}
+		i.quotas = append(i.quotas, req)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPut:
+		n := i.findQuota(user, clientID)
+		if n < 0 {
+			writeError(w, http.StatusNotFound, "quota not found")
+			return
+		}
+		var req api.Quota
+		if !readJSON(w, r, &req) {
+			return
+		}
+		req.User, req.ClientId = user, clientID
+		if msg := validQuota(req); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+		i.quotas[n] = req
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		n := i.findQuota(user, clientID)
+		if n < 0 {
+			writeError(w, http.StatusNotFound, "quota not found")
+			return
+		}
+		i.quotas = append(i.quotas[:n], i.quotas[n+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
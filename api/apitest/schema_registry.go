@@ -0,0 +1,416 @@
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"terraform-provider-cloudkarafka/api"
+)
+
+// schemaRegistry is the Schema Registry add-on of an instance. Compatibility
+// levels are stored but new versions are not checked against them.
+type schemaRegistry struct {
+	// schemas are indexed by id - 1, identical schemas share an id.
+	schemas       []api.Schema
+	subjects      map[string]*subject
+	compatibility string
+}
+
+type subject struct {
+	versions      []subjectVersion
+	compatibility string
+}
+
+type subjectVersion struct {
+	version int64
+	id      int64
+	deleted bool
+}
+
+// live returns the versions that are not soft deleted.
+func (s *subject) live() []subjectVersion {
+	var live []subjectVersion
+	for _, v := range s.versions {
+		if !v.deleted {
+			live = append(live, v)
+		}
+	}
+	return live
+}
+
+// EnableSchemaRegistry adds the Schema Registry add-on to an existing
+// instance. The registry is served below the fake's URL and accepts the
+// instance username and password.
+func (s *Server) EnableSchemaRegistry(instanceID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.mustInstance(instanceID)
+	i.SchemaRegistryUrl = fmt.Sprintf("%s/schema-registry/%d", s.URL, instanceID)
+	i.registry = &schemaRegistry{
+		subjects:      make(map[string]*subject),
+		compatibility: api.DefaultCompatibilityLevel,
+	}
+}
+
+// RegisterSchema registers a schema under a subject, as if done outside
+// Terraform, and returns its id and version.
+func (s *Server) RegisterSchema(instanceID int64, name string, schema api.Schema) (int64, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.mustRegistry(instanceID)
+	if msg := r.validSchema(schema); msg != "" {
+		panic("apitest: " + msg)
+	}
+	v := r.register(name, schema)
+	return v.id, v.version
+}
+
+// Versions returns the versions of a subject that are not soft deleted.
+func (s *Server) Versions(instanceID int64, name string) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var versions []int64
+	if sub, ok := s.mustRegistry(instanceID).subjects[name]; ok {
+		for _, v := range sub.live() {
+			versions = append(versions, v.version)
+		}
+	}
+	return versions
+}
+
+// SubjectExists reports whether the subject has versions, including soft
+// deleted ones.
+func (s *Server) SubjectExists(instanceID int64, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.mustRegistry(instanceID).subjects[name]
+	return ok && len(sub.versions) > 0
+}
+
+// Compatibility returns the compatibility level of a subject, or the global
+// level for an empty subject. It is empty when the subject has none.
+func (s *Server) Compatibility(instanceID int64, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.mustRegistry(instanceID)
+	if name == "" {
+		return r.compatibility
+	}
+	if sub, ok := r.subjects[name]; ok {
+		return sub.compatibility
+	}
+	return ""
+}
+
+// SetCompatibility sets the compatibility level of a subject, or the global
+// level for an empty subject, as if done outside Terraform.
+func (s *Server) SetCompatibility(instanceID int64, name, level string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.mustRegistry(instanceID)
+	if name == "" {
+		r.compatibility = level
+		return
+	}
+	sub, ok := r.subjects[name]
+	if !ok {
+		sub = &subject{}
+		r.subjects[name] = sub
+	}
+	sub.compatibility = level
+}
+
+func (s *Server) mustRegistry(instanceID int64) *schemaRegistry {
+	r := s.mustInstance(instanceID).registry
+	if r == nil {
+		panic(fmt.Sprintf("apitest: instance %d has no schema registry", instanceID))
+	}
+	return r
+}
+
+// find returns the version of the subject that holds the same schema.
+func (r *schemaRegistry) find(name string, schema api.Schema) (subjectVersion, bool) {
+	sub, ok := r.subjects[name]
+	if !ok {
+		return subjectVersion{}, false
+	}
+	for _, v := range sub.live() {
+		if sameSchema(r.schemas[v.id-1], schema) {
+			return v, true
+		}
+	}
+	return subjectVersion{}, false
+}
+
+func (r *schemaRegistry) register(name string, schema api.Schema) subjectVersion {
+	if v, ok := r.find(name, schema); ok {
+		return v
+	}
+	var id int64
+	for n, s := range r.schemas {
+		if sameSchema(s, schema) {
+			id = int64(n + 1)
+		}
+	}
+	if id == 0 {
+		r.schemas = append(r.schemas, api.Schema{
+			Schema:     schema.Schema,
+			SchemaType: schema.SchemaType,
+			References: schema.References,
+		})
+		id = int64(len(r.schemas))
+	}
+	sub, ok := r.subjects[name]
+	if !ok {
+		sub = &subject{}
+		r.subjects[name] = sub
+	}
+	// Versions keep counting after a soft delete.
+	v := subjectVersion{version: 1, id: id}
+	if n := len(sub.versions); n > 0 {
+		v.version = sub.versions[n-1].version + 1
+	}
+	sub.versions = append(sub.versions, v)
+	return v
+}
+
+// view returns a version as the registry reports it.
+func (r *schemaRegistry) view(name string, v subjectVersion) api.Schema {
+	s := r.schemas[v.id-1]
+	s.Subject, s.Id, s.Version = name, v.id, v.version
+	return s
+}
+
+// validSchema returns why the schema cannot be registered, or an empty
+// string.
+func (r *schemaRegistry) validSchema(s api.Schema) string {
+	switch s.SchemaType {
+	case "", "AVRO", "JSON":
+		if !json.Valid([]byte(s.Schema)) {
+			return "Invalid schema"
+		}
+	case "PROTOBUF":
+		if s.Schema == "" {
+			return "Invalid schema"
+		}
+	default:
+		return fmt.Sprintf("Unknown schema type %s", s.SchemaType)
+	}
+	for _, ref := range s.References {
+		sub, ok := r.subjects[ref.Subject]
+		if !ok {
+			return fmt.Sprintf("Invalid schema reference %s", ref.Name)
+		}
+		found := false
+		for _, v := range sub.live() {
+			found = found || v.version == ref.Version
+		}
+		if !found {
+			return fmt.Sprintf("Invalid schema reference %s", ref.Name)
+		}
+	}
+	return ""
+}
+
+// sameSchema reports whether two schemas would get the same id. Avro is the
+// default type.
+func sameSchema(a, b api.Schema) bool {
+	typeOf := func(s api.Schema) string {
+		if s.SchemaType == "" {
+			return "AVRO"
+		}
+		return s.SchemaType
+	}
+	return a.Schema == b.Schema && typeOf(a) == typeOf(b) &&
+		(len(a.References) == 0 && len(b.References) == 0 || reflect.DeepEqual(a.References, b.References))
+}
+
+func validCompatibility(level string) bool {
+	for _, l := range api.CompatibilityLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+func writeRegistryError(w http.ResponseWriter, status, code int, msg string) {
+	writeJSON(w, status, api.SchemaRegistryError{ErrorCode: code, Message: msg})
+}
+
+// serveSchemaRegistry serves /schema-registry/<instance_id>/..., parts is
+// the path after the prefix.
+func (s *Server) serveSchemaRegistry(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) < 2 {
+		writeRegistryError(w, http.StatusNotFound, 404, "not found")
+		return
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		writeRegistryError(w, http.StatusNotFound, 404, "not found")
+		return
+	}
+	i, ok := s.instances[id]
+	if !ok || i.registry == nil {
+		writeRegistryError(w, http.StatusNotFound, 404, "not found")
+		return
+	}
+	if user, password, ok := r.BasicAuth(); !ok || user != i.Username || password != i.Password {
+		writeRegistryError(w, http.StatusUnauthorized, 401, "Unauthorized")
+		return
+	}
+	reg := i.registry
+	switch parts[1] {
+	case "subjects":
+		reg.serveSubjects(w, r, parts[2:])
+	case "config":
+		reg.serveConfig(w, r, parts[2:])
+	default:
+		writeRegistryError(w, http.StatusNotFound, 404, "not found")
+	}
+}
+
+func (r *schemaRegistry) serveSubjects(w http.ResponseWriter, req *http.Request, parts []string) {
+	if len(parts) == 0 {
+		writeRegistryError(w, http.StatusNotFound, 404, "not found")
+		return
+	}
+	name := parts[0]
+	sub, exists := r.subjects[name]
+	switch {
+	case len(parts) == 1 && req.Method == http.MethodPost:
+		var schema api.Schema
+		if !readJSON(w, req, &schema) {
+			return
+		}
+		if !exists || len(sub.live()) == 0 {
+			writeRegistryError(w, http.StatusNotFound, 40401, fmt.Sprintf("Subject '%s' not found.", name))
+			return
+		}
+		v, ok := r.find(name, schema)
+		if !ok {
+			writeRegistryError(w, http.StatusNotFound, 40403, "Schema not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, r.view(name, v))
+	case len(parts) == 1 && req.Method == http.MethodDelete:
+		if !exists {
+			writeRegistryError(w, http.StatusNotFound, 40401, fmt.Sprintf("Subject '%s' not found.", name))
+			return
+		}
+		var deleted []int64
+		if req.URL.Query().Get("permanent") == "true" {
+			if len(sub.live()) > 0 {
+				writeRegistryError(w, http.StatusNotFound, 40405,
+					fmt.Sprintf("Subject '%s' was not deleted first before being permanently deleted", name))
+				return
+			}
+			for _, v := range sub.versions {
+				deleted = append(deleted, v.version)
+			}
+			delete(r.subjects, name)
+			writeJSON(w, http.StatusOK, deleted)
+			return
+		}
+		if len(sub.live()) == 0 {
+			writeRegistryError(w, http.StatusNotFound, 40404, fmt.Sprintf("Subject '%s' was soft deleted.", name))
+			return
+		}
+		for n := range sub.versions {
+			if !sub.versions[n].deleted {
+				sub.versions[n].deleted = true
+				deleted = append(deleted, sub.versions[n].version)
+			}
+		}
+		writeJSON(w, http.StatusOK, deleted)
+	case len(parts) == 2 && parts[1] == "versions" && req.Method == http.MethodPost:
+		var schema api.Schema
+		if !readJSON(w, req, &schema) {
+			return
+		}
+		if msg := r.validSchema(schema); msg != "" {
+			writeRegistryError(w, http.StatusUnprocessableEntity, 42201, msg)
+			return
+		}
+		v := r.register(name, schema)
+		writeJSON(w, http.StatusOK, map[string]int64{"id": v.id})
+	case len(parts) == 3 && parts[1] == "versions" && req.Method == http.MethodGet:
+		var live []subjectVersion
+		if exists {
+			live = sub.live()
+		}
+		if len(live) == 0 {
+			writeRegistryError(w, http.StatusNotFound, 40401, fmt.Sprintf("Subject '%s' not found.", name))
+			return
+		}
+		if parts[2] == "latest" {
+			writeJSON(w, http.StatusOK, r.view(name, live[len(live)-1]))
+			return
+		}
+		for _, v := range live {
+			if strconv.FormatInt(v.version, 10) == parts[2] {
+				writeJSON(w, http.StatusOK, r.view(name, v))
+				return
+			}
+		}
+		writeRegistryError(w, http.StatusNotFound, 40402, "Version not found.")
+	default:
+		writeRegistryError(w, http.StatusMethodNotAllowed, 405, "method not allowed")
+	}
+}
+
+func (r *schemaRegistry) serveConfig(w http.ResponseWriter, req *http.Request, parts []string) {
+	if len(parts) > 1 {
+		writeRegistryError(w, http.StatusNotFound, 404, "not found")
+		return
+	}
+	level := &r.compatibility
+	if len(parts) == 1 {
+		name := parts[0]
+		sub, ok := r.subjects[name]
+		if !ok {
+			if req.Method != http.MethodPut {
+				writeRegistryError(w, http.StatusNotFound, 40408, fmt.Sprintf("Subject '%s' does not have subject-level compatibility configured", name))
+				return
+			}
+			// A subject can be configured before its first version.
+			sub = &subject{}
+			r.subjects[name] = sub
+		}
+		level = &sub.compatibility
+	}
+	switch req.Method {
+	case http.MethodGet:
+		if *level == "" {
+			writeRegistryError(w, http.StatusNotFound, 40408, "Subject does not have subject-level compatibility configured")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"compatibilityLevel": *level})
+	case http.MethodPut:
+		var body struct {
+			Compatibility string `json:"compatibility"`
+		}
+		if !readJSON(w, req, &body) {
+			return
+		}
+		if !validCompatibility(body.Compatibility) {
+			writeRegistryError(w, http.StatusUnprocessableEntity, 42203, "Invalid compatibility level")
+			return
+		}
+		*level = body.Compatibility
+		writeJSON(w, http.StatusOK, body)
+	case http.MethodDelete:
+		if len(parts) == 0 || *level == "" {
+			writeRegistryError(w, http.StatusNotFound, 40408, "Subject does not have subject-level compatibility configured")
+			return
+		}
+		previous := *level
+		*level = ""
+		writeJSON(w, http.StatusOK, map[string]string{"compatibilityLevel": previous})
+	default:
+		writeRegistryError(w, http.StatusMethodNotAllowed, 405, "method not allowed")
+	}
+}
@@ -0,0 +1,450 @@
+package apitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"terraform-provider-cloudkarafka/api"
+)
+
+func newClient(s *Server) *api.API {
+	return api.New(s.URL, "key", api.WithPollInterval(time.Millisecond), api.WithRetries(2, time.Second))
+}
+
+func TestCreateInstanceWaitsUntilReady(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetReadyAfter(3)
+
+	instance, err := newClient(s).CreateInstance(context.Background(), api.CreateInstanceRequest{
+		Name:   "test",
+		Plan:   "ducky",
+		Region: "amazon-web-services::us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	polls := 0
+	for _, r := range s.Requests() {
+		if r == "GET /api/instances/1/cluster/status" {
+			polls++
+		}
+	}
+	if polls != 4 {
+		t.Errorf("polled cluster status %d times, want 4", polls)
+	}
+	if instance.KafkaVersion == "" || len(instance.Brokers()) != 2 {
+		t.Errorf("unexpected instance %+v", instance)
+	}
+}
+
+func TestDeleteInstanceWaitsUntilGone(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetReadyAfter(2)
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+
+	if err := newClient(s).DeleteInstance(context.Background(), id, true); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"DELETE /api/instances/1?keep_vpc=true",
+		"GET /api/instances/1",
+		"GET /api/instances/1",
+		"GET /api/instances/1",
+	}
+	if got := s.Requests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests %v, want %v", got, want)
+	}
+	if _, ok := s.Instance(id); ok {
+		t.Error("instance still exists")
+	}
+}
+
+func TestTopicLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetReadyAfter(2)
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+	client := newClient(s)
+	ctx := context.Background()
+
+	err := client.CreateTopic(ctx, id, api.Topic{Name: "orders", Partitions: 1, Replicas: 1, Config: api.Hash{"retention.ms": int64(1000), "cleanup.policy": "delete"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateTopic(ctx, id, api.Topic{Name: "orders", Partitions: 1, Replicas: 1}); err == nil {
+		t.Error("creating a duplicate topic succeeded")
+	}
+	if err := client.UpdateTopic(ctx, id, "orders", api.UpdateTopicRequest{Partitions: 3}); err != nil {
+		t.Fatal(err)
+	}
+	topic, err := client.ReadTopic(ctx, id, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Settings at the default are left out.
+	if _, ok := topic.Config["cleanup.policy"]; ok || topic.Partitions != 3 || topic.Config["retention.ms"] != "1000" {
+		t.Errorf("unexpected topic %+v", topic)
+	}
+	if err := client.DeleteTopic(ctx, id, "orders"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ReadTopic(ctx, id, "orders"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("ReadTopic after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFaultIsRetried(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+	s.Inject(Fault{Method: "GET", Path: "/api/instances/1/users", Status: 503, RetryAfter: "0", Times: 2})
+
+	if _, err := newClient(s).ReadUser(context.Background(), id, "alice"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("ReadUser = %v, want ErrNotFound after retries", err)
+	}
+	if n := len(s.Requests()); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+}
+
+func TestFaultIsReturned(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+	s.Inject(Fault{Method: "POST", Status: 500})
+
+	_, err := newClient(s).CreateUser(context.Background(), id, api.User{Name: "alice"})
+	if err == nil {
+		t.Fatal("CreateUser succeeded, want error")
+	}
+	if len(s.Requests()) != 1 {
+		t.Errorf("POST was retried: %v", s.Requests())
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	_, err := api.New(s.URL, "").ListInstances(context.Background())
+	if err == nil {
+		t.Fatal("ListInstances without API key succeeded")
+	}
+}
+
+func TestUpdateInstanceInPhases(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetReadyAfter(1)
+	id := s.AddInstance(api.InstanceResponse{Name: "test", Plan: "ducky", DiskSize: 128})
+
+	current := api.UpdateInstanceRequest{Name: "test", Plan: "ducky", DiskSize: 128}
+	wanted := api.UpdateInstanceRequest{Name: "renamed", Tags: []string{"prod"}, Plan: "bat", DiskSize: 256}
+	applied, err := newClient(s).UpdateInstance(context.Background(), id, current, wanted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(applied, wanted) {
+		t.Errorf("applied %+v, want %+v", applied, wanted)
+	}
+	var puts, polls int
+	for _, r := range s.Requests() {
+		switch r {
+		case "PUT /api/instances/1":
+			puts++
+		case "GET /api/instances/1/cluster/status":
+			polls++
+		}
+	}
+	if puts != 3 || polls != 6 {
+		t.Errorf("got %d updates and %d status polls, want 3 and 6", puts, polls)
+	}
+}
+
+func TestUpdateInstanceUpgradesKafka(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetReadyAfter(2)
+	id := s.AddInstance(api.InstanceResponse{Name: "test", Plan: "ducky", KafkaVersion: "3.4.1"})
+
+	current := api.UpdateInstanceRequest{Name: "test", Plan: "ducky", KafkaVersion: "3.4.1"}
+	wanted := api.UpdateInstanceRequest{Name: "test", Plan: "ducky", KafkaVersion: "3.6.1"}
+	if _, err := newClient(s).UpdateInstance(context.Background(), id, current, wanted); err != nil {
+		t.Fatal(err)
+	}
+	if instance, _ := s.Instance(id); instance.KafkaVersion != "3.6.1" {
+		t.Errorf("instance runs Kafka %s, want 3.6.1", instance.KafkaVersion)
+	}
+	want := []string{
+		"PUT /api/instances/1/actions/upgrade-kafka",
+		"GET /api/instances/1/cluster/status",
+		"GET /api/instances/1/cluster/status",
+		"GET /api/instances/1/cluster/status",
+	}
+	if got := s.Requests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests %v, want %v", got, want)
+	}
+
+	current, wanted = wanted, api.UpdateInstanceRequest{Name: "test", Plan: "ducky", KafkaVersion: "3.5.1"}
+	if _, err := newClient(s).UpdateInstance(context.Background(), id, current, wanted); err == nil {
+		t.Error("downgrade succeeded, want error")
+	}
+}
+
+func TestUpdateInstanceStopsAtFailedPhase(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.AddInstance(api.InstanceResponse{Name: "test", Plan: "ducky", DiskSize: 128})
+	s.Inject(Fault{Method: "PUT", Status: 400, Skip: 1})
+
+	current := api.UpdateInstanceRequest{Name: "test", Plan: "ducky", DiskSize: 128}
+	wanted := api.UpdateInstanceRequest{Name: "renamed", Plan: "bat", DiskSize: 256}
+	applied, err := newClient(s).UpdateInstance(context.Background(), id, current, wanted)
+	if err == nil {
+		t.Fatal("UpdateInstance succeeded, want error")
+	}
+	want := api.UpdateInstanceRequest{Name: "renamed", Plan: "ducky", DiskSize: 128}
+	if !reflect.DeepEqual(applied, want) {
+		t.Errorf("applied %+v, want %+v", applied, want)
+	}
+	if instance, _ := s.Instance(id); instance.Plan != "ducky" || instance.DiskSize != 128 {
+		t.Errorf("later phases ran: %+v", instance)
+	}
+}
+
+func TestVPCPeeringWaitsUntilSettled(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetReadyAfter(2)
+	client := newClient(s)
+	ctx := context.Background()
+
+	vpc, err := client.CreateVPC(ctx, api.CreateVPCRequest{Name: "net", Region: "amazon-web-services::us-east-1", Subnet: "10.0.0.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	peering, err := client.RequestVPCPeering(ctx, vpc.Id, api.VPCPeering{PeerNetworkId: "vpc-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peering.Status != api.VPCPeeringPendingAcceptance {
+		t.Errorf("AWS peering is %s, want it to wait for acceptance", peering.Status)
+	}
+	if err := client.DeleteVPC(ctx, vpc.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ReadVPC(ctx, vpc.Id); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("ReadVPC() after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateFirewallWaitsUntilConfigured(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetReadyAfter(2)
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+
+	rules := []api.FirewallRule{{IP: "203.0.113.7/32", Services: []string{"KAFKA"}}}
+	if err := newClient(s).UpdateFirewall(context.Background(), id, rules); err != nil {
+		t.Fatal(err)
+	}
+	polls := 0
+	for _, r := range s.Requests() {
+		if r == "GET /api/instances/1/security/firewall/configured" {
+			polls++
+		}
+	}
+	if polls != 3 {
+		t.Errorf("polled firewall %d times, want 3", polls)
+	}
+
+	rules[0].Services = []string{"SSH"}
+	if err := newClient(s).UpdateFirewall(context.Background(), id, rules); err == nil {
+		t.Error("unknown service was accepted")
+	}
+}
+
+func TestRotateUserCredentials(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+	client := newClient(s)
+	ctx := context.Background()
+
+	created, err := client.CreateUser(ctx, id, api.User{Name: "alice", Type: "sasl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := client.RotateUserCredentials(ctx, id, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.Password == "" || rotated.Password == created.Password {
+		t.Errorf("rotated password = %q, created %q", rotated.Password, created.Password)
+	}
+	set, err := client.RotateUserCredentials(ctx, id, "alice", "s3cret-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Password != "s3cret-pass" {
+		t.Errorf("password = %q, want s3cret-pass", set.Password)
+	}
+	read, err := client.ReadUser(ctx, id, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Password != "" {
+		t.Error("ReadUser() returned the password")
+	}
+	if _, err := client.RotateUserCredentials(ctx, id, "bob", ""); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("RotateUserCredentials() for missing user = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetAclRulesBatchesAdds(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+	keep := api.AclRule{User: "alice", Operation: "read", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "literal"}
+	s.AddAclRule(id, keep)
+	stale := s.AddAclRule(id, api.AclRule{User: "alice", Operation: "write", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "literal"})
+	s.AddAclRule(id, api.AclRule{User: "bob", Operation: "all", Resource: "cluster", ResourcePattern: "kafka-cluster", ResourcePatternType: "literal"})
+
+	rules := []api.AclRule{keep}
+	for _, topic := range []string{"a", "b", "c"} {
+		rules = append(rules, api.AclRule{Operation: "read", Resource: "topic", ResourcePattern: topic, ResourcePatternType: "literal"})
+	}
+	if err := newClient(s).SetAclRules(context.Background(), id, "alice", rules); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"GET /api/instances/1/acls",
+		"POST /api/instances/1/acls",
+		fmt.Sprintf("DELETE /api/instances/1/acls/%d", stale),
+	}
+	if got := s.Requests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+	if n := len(s.AclRules(id)); n != 5 {
+		t.Errorf("server has %d rules, want 5", n)
+	}
+}
+
+func TestQuotaLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.AddInstance(api.InstanceResponse{Name: "test"})
+	client := newClient(s)
+	ctx := context.Background()
+
+	quota := api.Quota{User: "alice", ClientId: "orders app", ProducerByteRate: api.Int64(1024)}
+	if err := client.CreateQuota(ctx, id, quota); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateQuota(ctx, id, quota); err == nil {
+		t.Error("creating a duplicate quota succeeded")
+	}
+	quota.ProducerByteRate, quota.RequestPercentage = nil, api.Float64(12.5)
+	if err := client.UpdateQuota(ctx, id, quota); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.ReadQuota(ctx, id, "alice", "orders app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ProducerByteRate != nil || got.RequestPercentage == nil || *got.RequestPercentage != 12.5 {
+		t.Errorf("unexpected quota %+v", got)
+	}
+	if _, err := client.ReadQuota(ctx, id, "alice", ""); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("ReadQuota for the user alone = %v, want ErrNotFound", err)
+	}
+	if err := client.DeleteQuota(ctx, id, "alice", "orders app"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteQuota(ctx, id, "alice", "orders app"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("DeleteQuota after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSchemaRegistryLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.AddInstance(api.InstanceResponse{Name: "test", Username: "abcdefgh", Password: "secret"})
+	client := newClient(s)
+	ctx := context.Background()
+
+	if _, err := client.SchemaRegistry(ctx, id); err == nil {
+		t.Fatal("got a Schema Registry client for an instance without the add-on")
+	}
+	s.EnableSchemaRegistry(id)
+	registry, err := client.SchemaRegistry(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := api.Schema{Schema: `{"type":"string"}`}
+	first, err := registry.RegisterSchema(ctx, "orders-key", v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := registry.RegisterSchema(ctx, "orders-key", v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Version != 1 || again.Id != first.Id || again.Version != 1 {
+		t.Errorf("registered %+v then %+v, want the same version 1", first, again)
+	}
+	second, err := registry.RegisterSchema(ctx, "orders-key", api.Schema{Schema: `{"type":"long"}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := registry.ReadSchema(ctx, "orders-key", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Id != second.Id || latest.Version != 2 || latest.Schema != `{"type":"long"}` {
+		t.Errorf("unexpected latest version %+v", latest)
+	}
+	ref := api.Schema{
+		Schema:     `{"type":"record","name":"Order","fields":[{"name":"key","type":"Key"}]}`,
+		References: []api.SchemaReference{{Name: "Key", Subject: "orders-key", Version: 3}},
+	}
+	if _, err := registry.RegisterSchema(ctx, "orders-value", ref); err == nil {
+		t.Error("registering a schema with a missing reference succeeded")
+	}
+
+	if err := registry.SetCompatibility(ctx, "orders-key", "FULL"); err != nil {
+		t.Fatal(err)
+	}
+	if level, err := registry.ReadCompatibility(ctx, "orders-key"); err != nil || level != "FULL" {
+		t.Errorf("ReadCompatibility = %q, %v, want FULL", level, err)
+	}
+	if err := registry.ResetCompatibility(ctx, "orders-key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.ReadCompatibility(ctx, "orders-key"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("ReadCompatibility after reset = %v, want ErrNotFound", err)
+	}
+	if level, err := registry.ReadCompatibility(ctx, ""); err != nil || level != api.DefaultCompatibilityLevel {
+		t.Errorf("global ReadCompatibility = %q, %v, want %s", level, err, api.DefaultCompatibilityLevel)
+	}
+
+	if err := registry.DeleteSubject(ctx, "orders-key", true); err != nil {
+		t.Fatal(err)
+	}
+	if s.SubjectExists(id, "orders-key") {
+		t.Error("subject still exists after a permanent delete")
+	}
+	if _, err := registry.ReadSchema(ctx, "orders-key", 0); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("ReadSchema after delete = %v, want ErrNotFound", err)
+	}
+	if err := registry.DeleteSubject(ctx, "orders-key", false); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("DeleteSubject after delete = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,237 @@
+package apitest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-cloudkarafka/api"
+)
+
+type vpc struct {
+	api.VPCResponse
+	// owner is the instance the VPC was created for, zero for VPCs created
+	// on their own. Owned VPCs are deleted with the instance unless kept.
+	owner       int64
+	peerings    map[string]*peering
+	nextPeering int
+}
+
+type peering struct {
+	api.VPCPeering
+	// next is the status reported once pending reaches zero.
+	next    string
+	pending int
+}
+
+// AddVPC stores a VPC and returns its id. The id, if zero, is assigned by
+// the server.
+func (s *Server) AddVPC(v api.VPCResponse) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addVPC(v, 0).Id
+}
+
+// AddVPCPeeringRequest stores a peering request made from the peer side of
+// an existing VPC, waiting to be accepted, and returns its id.
+func (s *Server) AddVPCPeeringRequest(vpcID int64, p api.VPCPeering) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := s.mustVPC(vpcID)
+	p.Status = api.VPCPeeringPendingAcceptance
+	return v.addPeering(p, api.VPCPeeringPendingAcceptance, 0).Id
+}
+
+// VPC returns a copy of the stored VPC.
+func (s *Server) VPC(id int64) (api.VPCResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.vpcs[id]
+	if !ok {
+		return api.VPCResponse{}, false
+	}
+	return v.VPCResponse, true
+}
+
+// VPCPeering returns a stored peering as the API reports it.
+func (s *Server) VPCPeering(vpcID int64, id string) (api.VPCPeering, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.vpcs[vpcID]
+	if !ok {
+		return api.VPCPeering{}, false
+	}
+	p, ok := v.peerings[id]
+	if !ok {
+		return api.VPCPeering{}, false
+	}
+	return p.view(), true
+}
+
+func (s *Server) mustVPC(id int64) *vpc {
+	v, ok := s.vpcs[id]
+	if !ok {
+		panic(fmt.Sprintf("apitest: no VPC with id %d", id))
+	}
+	return v
+}
+
+func (s *Server) addVPC(r api.VPCResponse, owner int64) *vpc {
+	if r.Id == 0 {
+		r.Id = s.nextVPC
+	}
+	if r.Id >= s.nextVPC {
+		s.nextVPC = r.Id + 1
+	}
+	v := &vpc{VPCResponse: r, owner: owner, peerings: make(map[string]*peering), nextPeering: 1}
+	s.vpcs[r.Id] = v
+	return v
+}
+
+// vpcInUse reports whether any instance runs in the VPC.
+func (s *Server) vpcInUse(id int64) bool {
+	for _, i := range s.instances {
+		if i.Vpc.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *vpc) addPeering(p api.VPCPeering, next string, pending int) *peering {
+	p.Id = fmt.Sprintf("pcx-%d-%d", v.Id, v.nextPeering)
+	v.nextPeering++
+	stored := &peering{VPCPeering: p, next: next, pending: pending}
+	v.peerings[p.Id] = stored
+	return stored
+}
+
+// info describes the CloudKarafka side of the VPC in the terms of its cloud.
+func (v *vpc) info() api.VPCPeeringInfo {
+	info := api.VPCPeeringInfo{Region: v.Region, Subnet: v.Subnet}
+	cloud, _, _ := strings.Cut(v.Region, "::")
+	switch cloud {
+	case "google-compute-engine":
+		info.AccountId = "cloudkarafka"
+		info.NetworkId = fmt.Sprintf("projects/cloudkarafka/global/networks/vpc-%d", v.Id)
+	case "azure-arm":
+		info.AccountId = "00000000-0000-0000-0000-000000000000"
+		info.NetworkId = fmt.Sprintf("/subscriptions/%s/resourceGroups/vpc-%d/providers/Microsoft.Network/virtualNetworks/vpc-%d", info.AccountId, v.Id, v.Id)
+	default:
+		info.AccountId = "123456789012"
+		info.NetworkId = fmt.Sprintf("vpc-%08d", v.Id)
+	}
+	return info
+}
+
+func (s *Server) serveVPCs(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			ids := make([]int64, 0, len(s.vpcs))
+			for id := range s.vpcs {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+			list := make([]api.VPCResponse, 0, len(ids))
+			for _, id := range ids {
+				list = append(list, s.vpcs[id].VPCResponse)
+			}
+			writeJSON(w, http.StatusOK, list)
+		case http.MethodPost:
+			var req api.CreateVPCRequest
+			if !readJSON(w, r, &req) {
+				return
+			}
+			if req.Name == "" || req.Region == "" || req.Subnet == "" {
+				writeError(w, http.StatusBadRequest, "name, region and subnet are required")
+				return
+			}
+			v := s.addVPC(api.VPCResponse{Name: req.Name, Region: req.Region, Subnet: req.Subnet, Tags: req.Tags}, 0)
+			writeJSON(w, http.StatusOK, map[string]interface{}{"id": v.Id})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	v, ok := s.vpcs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "VPC not found")
+		return
+	}
+	route := strings.Join(parts[1:], "/")
+	switch {
+	case route == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, v.VPCResponse)
+	case route == "" && r.Method == http.MethodDelete:
+		if s.vpcInUse(id) {
+			writeError(w, http.StatusBadRequest, "VPC is used by instances")
+			return
+		}
+		delete(s.vpcs, id)
+		w.WriteHeader(http.StatusNoContent)
+	case route == "peering-info" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, v.info())
+	case route == "peerings" && r.Method == http.MethodPost:
+		var req api.VPCPeering
+		if !readJSON(w, r, &req) {
+			return
+		}
+		if req.PeerNetworkId == "" {
+			writeError(w, http.StatusBadRequest, "peer_network_id is required")
+			return
+		}
+		// AWS peerings requested from CloudKarafka must be accepted by the
+		// owner of the peer VPC, the other clouds peer from both sides.
+		next := api.VPCPeeringActive
+		if cloud, _, _ := strings.Cut(v.Region, "::"); cloud == "amazon-web-services" {
+			next = api.VPCPeeringPendingAcceptance
+		}
+		req.Status, req.StatusMessage = api.VPCPeeringPending, ""
+		p := v.addPeering(req, next, s.readyAfter)
+		writeJSON(w, http.StatusCreated, p.view())
+	case len(parts) >= 3 && parts[1] == "peerings":
+		p, ok := v.peerings[parts[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "peering not found")
+			return
+		}
+		switch {
+		case len(parts) == 3 && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, p.view())
+			if p.pending > 0 {
+				p.pending--
+			}
+		case len(parts) == 3 && r.Method == http.MethodDelete:
+			delete(v.peerings, p.Id)
+			w.WriteHeader(http.StatusNoContent)
+		case len(parts) == 4 && parts[3] == "accept" && r.Method == http.MethodPut:
+			if p.view().Status != api.VPCPeeringPendingAcceptance {
+				writeError(w, http.StatusBadRequest, "peering is not waiting to be accepted")
+				return
+			}
+			p.Status, p.next, p.pending = api.VPCPeeringPending, api.VPCPeeringActive, s.readyAfter
+			w.WriteHeader(http.StatusOK)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// view returns the peering as reported by the API.
+func (p *peering) view() api.VPCPeering {
+	v := p.VPCPeering
+	if p.pending == 0 {
+		v.Status = p.next
+	}
+	return v
+}
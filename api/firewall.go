@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// FirewallServices are the services a firewall rule can open.
+var FirewallServices = []string{"KAFKA", "KAFKA_SSL", "SCHEMA_REGISTRY", "KAFKA_REST"}
+
+// FirewallRule opens services and ports to the addresses in IP, which is a
+// CIDR block.
+type FirewallRule struct {
+	IP          string   `json:"ip"`
+	Services    []string `json:"services"`
+	Ports       []int64  `json:"ports"`
+	Description string   `json:"description"`
+}
+
+func (api *API) ReadFirewall(ctx context.Context, instanceId int64) ([]FirewallRule, error) {
+	var (
+		data   []FirewallRule
+		failed APIError
+	)
+	path := fmt.Sprintf("/api/instances/%d/security/firewall", instanceId)
+	resp, err := api.request(ctx).Get(path).Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("instance with id %d %w", instanceId, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return nil, failed
+	}
+	return data, nil
+}
+
+// UpdateFirewall replaces all firewall rules of the instance and waits until
+// the brokers have applied them.
+func (api *API) UpdateFirewall(ctx context.Context, instanceId int64, rules []FirewallRule) error {
+	var failed APIError
+	if rules == nil {
+		rules = []FirewallRule{}
+	}
+	path := fmt.Sprintf("/api/instances/%d/security/firewall", instanceId)
+	resp, err := api.request(ctx).Put(path).BodyJSON(rules).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 204 {
+		return failed
+	}
+	return api.waitUntilFirewallConfigured(ctx, instanceId)
+}
+
+// ResetFirewall puts back the default rules, which open all services to any
+// address, and waits until the brokers have applied them.
+func (api *API) ResetFirewall(ctx context.Context, instanceId int64) error {
+	var failed APIError
+	path := fmt.Sprintf("/api/instances/%d/security/firewall", instanceId)
+	resp, err := api.request(ctx).Delete(path).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 204 {
+		return failed
+	}
+	return api.waitUntilFirewallConfigured(ctx, instanceId)
+}
+
+func (api *API) waitUntilFirewallConfigured(ctx context.Context, instanceId int64) error {
+	path := fmt.Sprintf("/api/instances/%d/security/firewall/configured", instanceId)
+	for {
+		var data struct {
+			Configured bool `json:"configured"`
+		}
+		var failed APIError
+		resp, err := api.request(ctx).Get(path).Receive(&data, &failed)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != 200 {
+			return failed
+		}
+		if data.Configured {
+			return nil
+		}
+		tflog.Info(ctx, fmt.Sprintf("Waiting for the firewall of instance %d to be configured", instanceId))
+		if err := sleep(ctx, api.instancePoll); err != nil {
+			return fmt.Errorf("waiting for the firewall of instance %d: %w", instanceId, err)
+		}
+	}
+}
@@ -1,16 +1,41 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"time"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type ClusterStatus struct {
+	Name       string       `json:"name"`
+	Ready      bool         `json:"ready"`
+	Configured bool         `json:"configured"`
+	Nodes      []NodeStatus `json:"nodes"`
+}
+
+type NodeStatus struct {
 	Name       string `json:"name"`
 	Ready      bool   `json:"ready"`
 	Configured bool   `json:"configured"`
 }
 
+// readyNodes splits the nodes into those that are ready and those that are not.
+func (s ClusterStatus) readyNodes() (ready, pending []string) {
+	ready, pending = []string{}, []string{}
+	for _, n := range s.Nodes {
+		if n.Ready && n.Configured {
+			ready = append(ready, n.Name)
+		} else {
+			pending = append(pending, n.Name)
+		}
+	}
+	return ready, pending
+}
+
 type VPC struct {
 	Id     int64  `json:"id"`
 	Subnet string `json:"subnet"`
@@ -23,11 +48,26 @@ type InstanceResponse struct {
 	Region       string   `json:"region"`
 	Tags         []string `json:"tags"`
 	KafkaVersion string   `json:"kafka_version"`
+	DiskSize     int64    `json:"disk_size"`
 	ApiKey       string   `json:"apikey"`
 	BrokerUrl    string   `json:"brokers"`
 	Password     string   `json:"password"`
 	Username     string   `json:"username"`
 	Vpc          VPC      `json:"vpc"`
+	// SchemaRegistryUrl is only set when the Schema Registry add-on is
+	// enabled.
+	SchemaRegistryUrl string `json:"schema_registry_url,omitempty"`
+}
+
+// Brokers splits the comma separated broker string into host:port entries.
+func (i InstanceResponse) Brokers() []string {
+	var brokers []string
+	for _, b := range strings.Split(i.BrokerUrl, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	return brokers
 }
 
 type CreateInstanceRequest struct {
@@ -46,33 +86,44 @@ type UpdateInstanceRequest struct {
 	Tags     []string `json:"tags"`
 	Plan     string   `json:"plan"`
 	DiskSize int64    `json:"disk_size"`
+	// KafkaVersion is not part of the PUT body, it is changed through the
+	// upgrade endpoint. Empty leaves the version as it is.
+	KafkaVersion string `json:"-"`
 }
 
-func (api *API) waitUntilReady(id int64) error {
+func (api *API) waitUntilReady(ctx context.Context, id int64) error {
 	var data ClusterStatus
 	for {
-		time.Sleep(10 * time.Second)
+		if err := sleep(ctx, api.instancePoll); err != nil {
+			return fmt.Errorf("waiting for instance %d to be ready: %w", id, err)
+		}
 		path := fmt.Sprintf("api/instances/%d/cluster/status", id)
-		_, err := api.client.New().Get(path).ReceiveSuccess(&data)
+		data = ClusterStatus{}
+		_, err := api.request(ctx).Get(path).ReceiveSuccess(&data)
 		if err != nil {
 			return err
 		}
 		if data.Configured && data.Ready {
 			return nil
 		}
+		ready, pending := data.readyNodes()
+		tflog.Info(ctx, fmt.Sprintf("Waiting for instance %d, %d of %d nodes ready", id, len(ready), len(data.Nodes)), map[string]interface{}{
+			"ready_nodes":   ready,
+			"pending_nodes": pending,
+		})
 	}
 }
 
-func (api *API) readInstance(id int64) (InstanceResponse, error) {
+func (api *API) readInstance(ctx context.Context, id int64) (InstanceResponse, error) {
 	var data InstanceResponse
 	var error APIError
 	path := fmt.Sprintf("api/instances/%d", id)
-	response, err := api.client.New().Get(path).Receive(&data, &error)
+	response, err := api.request(ctx).Get(path).Receive(&data, &error)
 	if err != nil {
 		return InstanceResponse{}, err
 	}
 	if response.StatusCode == 404 {
-		return InstanceResponse{}, fmt.Errorf("Instance with id %d not found", id)
+		return InstanceResponse{}, fmt.Errorf("instance with id %d %w", id, ErrNotFound)
 	}
 	if response.StatusCode != 200 {
 		return InstanceResponse{}, fmt.Errorf("failed to fetch info about instance: %s", error.Error())
@@ -80,14 +131,14 @@ func (api *API) readInstance(id int64) (InstanceResponse, error) {
 	return data, nil
 }
 
-func (api *API) CreateInstance(req CreateInstanceRequest) (InstanceResponse, error) {
+func (api *API) CreateInstance(ctx context.Context, req CreateInstanceRequest) (InstanceResponse, error) {
 	var (
 		data   map[string]interface{}
 		failed APIError
 	)
 	resp := InstanceResponse{}
 
-	response, err := api.client.New().Post("/api/instances").BodyJSON(req).Receive(&data, &failed)
+	response, err := api.request(ctx).Post("/api/instances").BodyJSON(req).Receive(&data, &failed)
 	if err != nil {
 		return resp, err
 	}
@@ -102,23 +153,118 @@ func (api *API) CreateInstance(req CreateInstanceRequest) (InstanceResponse, err
 	}
 	instanceId := int64(data["id"].(float64))
 	for {
-		if err := api.waitUntilReady(instanceId); err != nil {
+		if err := api.waitUntilReady(ctx, instanceId); err != nil {
 			return resp, err
 		} else {
 			break
 		}
 	}
-	return api.readInstance(instanceId)
+	return api.readInstance(ctx, instanceId)
+}
+
+func (api *API) ReadInstance(ctx context.Context, id int64) (InstanceResponse, error) {
+	return api.readInstance(ctx, id)
+}
+
+func (api *API) ListInstances(ctx context.Context) ([]InstanceResponse, error) {
+	var (
+		data   []InstanceResponse
+		failed APIError
+	)
+	response, err := api.request(ctx).Get("/api/instances").Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == 401 {
+		return nil, fmt.Errorf("Authentication error: %s", "invalid API key used")
+	}
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to list instances: %s", failed.Error())
+	}
+	return data, nil
+}
+
+// UpdateInstance moves an instance from the current settings to the wanted
+// ones in phases: name and tags first, then the plan, then the disk size and
+// last the Kafka version, which restarts the brokers one at a time. After
+// each phase it waits for the cluster to be ready again. It returns the
+// settings that were applied, which are not the wanted ones if a phase failed.
+func (api *API) UpdateInstance(ctx context.Context, id int64, current, wanted UpdateInstanceRequest) (UpdateInstanceRequest, error) {
+	phases := []struct {
+		name    string
+		changed bool
+		apply   func(*UpdateInstanceRequest)
+		// send applies the phase, nil means a PUT of the whole request.
+		send func(UpdateInstanceRequest) error
+	}{
+		{
+			name:    "name and tags",
+			changed: current.Name != wanted.Name || !sameTags(current.Tags, wanted.Tags),
+			apply:   func(r *UpdateInstanceRequest) { r.Name, r.Tags = wanted.Name, wanted.Tags },
+		},
+		{
+			name:    "plan",
+			changed: current.Plan != wanted.Plan,
+			apply:   func(r *UpdateInstanceRequest) { r.Plan = wanted.Plan },
+		},
+		{
+			name:    "disk size",
+			changed: current.DiskSize != wanted.DiskSize,
+			apply:   func(r *UpdateInstanceRequest) { r.DiskSize = wanted.DiskSize },
+		},
+		{
+			name:    "Kafka version",
+			changed: wanted.KafkaVersion != "" && current.KafkaVersion != wanted.KafkaVersion,
+			apply:   func(r *UpdateInstanceRequest) { r.KafkaVersion = wanted.KafkaVersion },
+			send: func(r UpdateInstanceRequest) error {
+				return api.upgradeKafka(ctx, id, r.KafkaVersion)
+			},
+		},
+	}
+	applied := current
+	for _, p := range phases {
+		if !p.changed {
+			continue
+		}
+		next := applied
+		p.apply(&next)
+		send := p.send
+		if send == nil {
+			send = func(r UpdateInstanceRequest) error { return api.putInstance(ctx, id, r) }
+		}
+		tflog.Info(ctx, fmt.Sprintf("Updating %s of instance %d", p.name, id))
+		if err := send(next); err != nil {
+			return applied, fmt.Errorf("updating %s: %w", p.name, err)
+		}
+		applied = next
+		if err := api.waitUntilReady(ctx, id); err != nil {
+			return applied, fmt.Errorf("waiting for instance after updating %s: %w", p.name, err)
+		}
+	}
+	return applied, nil
 }
 
-func (api *API) ReadInstance(id int64) (InstanceResponse, error) {
-	return api.readInstance(id)
+// sameTags reports whether a and b hold the same tags, in any order.
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
-func (api *API) UpdateInstance(id int64, data UpdateInstanceRequest) error {
+func (api *API) putInstance(ctx context.Context, id int64, data UpdateInstanceRequest) error {
 	var failed APIError
 	path := fmt.Sprintf("api/instances/%d", id)
-	response, err := api.client.New().Put(path).BodyJSON(data).Receive(nil, &failed)
+	response, err := api.request(ctx).Put(path).BodyJSON(data).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
@@ -131,19 +277,37 @@ func (api *API) UpdateInstance(id int64, data UpdateInstanceRequest) error {
 	if response.StatusCode != 200 {
 		return fmt.Errorf("update instance failed: %s", failed.Error())
 	}
-	return api.waitUntilReady(id)
+	return nil
 }
 
-func (api *API) DeleteInstance(id int64, keep_vpc bool) error {
+// DeleteInstance deletes the instance and waits until it is gone. With
+// keepVPC the VPC the instance ran in is left for other instances.
+func (api *API) DeleteInstance(ctx context.Context, id int64, keepVPC bool) error {
 	var failed APIError
-	path := fmt.Sprintf("api/instances/%d?keep_vpc=%v", id, keep_vpc)
-	response, err := api.client.New().Delete(path).Receive(nil, &failed)
+	path := fmt.Sprintf("api/instances/%d?keep_vpc=%v", id, keepVPC)
+	response, err := api.request(ctx).Delete(path).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
 	if response.StatusCode != 204 {
 		return fmt.Errorf("failed to delete instance: %s", failed.Error())
 	}
+	return api.waitUntilDeleted(ctx, id)
+}
 
-	return nil
+// waitUntilDeleted polls the instance until the API reports it as not found.
+func (api *API) waitUntilDeleted(ctx context.Context, id int64) error {
+	for {
+		_, err := api.readInstance(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		tflog.Info(ctx, fmt.Sprintf("Waiting for instance %d to be deleted", id))
+		if err := sleep(ctx, api.instancePoll); err != nil {
+			return fmt.Errorf("waiting for instance %d to be deleted: %w", id, err)
+		}
+	}
 }
@@ -1,108 +1,244 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// KafkaConfig holds the broker settings managed through the API. A nil field
+// is left untouched on the server. Properties holds any other broker setting,
+// keyed by its Kafka name.
 type KafkaConfig struct {
-	AutoCreateTopics  bool
-	MinInsyncReplicas int64
-	LogRetentionBytes int64
-	LogRetentionMs    int64
-	LogSegmentBytes   int64
-	NetworkThreads    int64
-	IOThreads         int64
-	MessageMaxBytes   int64
+	AutoCreateTopics  *bool
+	MinInsyncReplicas *int64
+	LogRetentionBytes *int64
+	LogRetentionMs    *int64
+	LogSegmentBytes   *int64
+	NetworkThreads    *int64
+	IOThreads         *int64
+	MessageMaxBytes   *int64
+	Properties        map[string]string
+}
+
+// KnownKafkaConfigKeys lists the settings that have a typed field in KafkaConfig.
+var KnownKafkaConfigKeys = []string{
+	"auto.create.topics.enable",
+	"min.insync.replicas",
+	"log.retention.bytes",
+	"log.retention.ms",
+	"log.segment.bytes",
+	"num.network.threads",
+	"num.io.threads",
+	"message.max.bytes",
 }
 
 func NewKafkaConfig() *KafkaConfig {
+	return &KafkaConfig{}
+}
+
+// DefaultKafkaConfig returns the Apache Kafka broker defaults.
+func DefaultKafkaConfig() *KafkaConfig {
 	return &KafkaConfig{
-		AutoCreateTopics:  false,
-		MinInsyncReplicas: -1,
-		LogRetentionBytes: -1,
-		LogRetentionMs:    -1,
-		LogSegmentBytes:   -1,
-		NetworkThreads:    -1,
-		IOThreads:         -1,
-		MessageMaxBytes:   -1,
+		AutoCreateTopics:  Bool(true),
+		MinInsyncReplicas: Int64(1),
+		LogRetentionBytes: Int64(-1),
+		LogRetentionMs:    Int64(604800000),
+		LogSegmentBytes:   Int64(1073741824),
+		NetworkThreads:    Int64(3),
+		IOThreads:         Int64(8),
+		MessageMaxBytes:   Int64(1048588),
+	}
+}
+
+// propertyDefaults holds the Apache Kafka broker defaults of the settings
+// without a typed field in KafkaConfig that are commonly set as properties.
+var propertyDefaults = map[string]string{
+	"auto.leader.rebalance.enable":      "true",
+	"compression.type":                  "producer",
+	"default.replication.factor":        "1",
+	"group.initial.rebalance.delay.ms":  "3000",
+	"log.cleaner.delete.retention.ms":   "86400000",
+	"log.cleaner.min.compaction.lag.ms": "0",
+	"log.cleaner.threads":               "1",
+	"log.cleanup.policy":                "delete",
+	"log.message.timestamp.type":        "CreateTime",
+	"log.retention.check.interval.ms":   "300000",
+	"num.partitions":                    "1",
+	"num.replica.fetchers":              "1",
+	"offsets.retention.minutes":         "10080",
+	"replica.lag.time.max.ms":           "30000",
+	"unclean.leader.election.enable":    "false",
+}
+
+// PropertyDefault returns the broker default of the property name, and
+// whether it is known.
+func PropertyDefault(name string) (string, bool) {
+	v, ok := propertyDefaults[name]
+	return v, ok
+}
+
+// Bool returns a pointer to v.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int64 returns a pointer to v.
+func Int64(v int64) *int64 {
+	return &v
+}
+
+// ResetToDefaults returns a config where every field set in me holds the
+// broker default instead. Properties without a known default are left out.
+func (me *KafkaConfig) ResetToDefaults() *KafkaConfig {
+	defaults := DefaultKafkaConfig()
+	cfg := NewKafkaConfig()
+	if me.AutoCreateTopics != nil {
+		cfg.AutoCreateTopics = defaults.AutoCreateTopics
+	}
+	if me.MinInsyncReplicas != nil {
+		cfg.MinInsyncReplicas = defaults.MinInsyncReplicas
+	}
+	if me.LogRetentionBytes != nil {
+		cfg.LogRetentionBytes = defaults.LogRetentionBytes
+	}
+	if me.LogRetentionMs != nil {
+		cfg.LogRetentionMs = defaults.LogRetentionMs
+	}
+	if me.LogSegmentBytes != nil {
+		cfg.LogSegmentBytes = defaults.LogSegmentBytes
+	}
+	if me.NetworkThreads != nil {
+		cfg.NetworkThreads = defaults.NetworkThreads
+	}
+	if me.IOThreads != nil {
+		cfg.IOThreads = defaults.IOThreads
+	}
+	if me.MessageMaxBytes != nil {
+		cfg.MessageMaxBytes = defaults.MessageMaxBytes
+	}
+	for k := range me.Properties {
+		if v, ok := PropertyDefault(k); ok {
+			if cfg.Properties == nil {
+				cfg.Properties = make(map[string]string)
+			}
+			cfg.Properties[k] = v
+		}
+	}
+	return cfg
+}
+
+// IsEmpty reports whether me sets nothing.
+func (me *KafkaConfig) IsEmpty() bool {
+	return me.AsProperties() == ""
+}
+
+// Merge copies every field set in other into me.
+func (me *KafkaConfig) Merge(other *KafkaConfig) {
+	if other.AutoCreateTopics != nil {
+		me.AutoCreateTopics = other.AutoCreateTopics
+	}
+	if other.MinInsyncReplicas != nil {
+		me.MinInsyncReplicas = other.MinInsyncReplicas
+	}
+	if other.LogRetentionBytes != nil {
+		me.LogRetentionBytes = other.LogRetentionBytes
+	}
+	if other.LogRetentionMs != nil {
+		me.LogRetentionMs = other.LogRetentionMs
+	}
+	if other.LogSegmentBytes != nil {
+		me.LogSegmentBytes = other.LogSegmentBytes
+	}
+	if other.NetworkThreads != nil {
+		me.NetworkThreads = other.NetworkThreads
+	}
+	if other.IOThreads != nil {
+		me.IOThreads = other.IOThreads
+	}
+	if other.MessageMaxBytes != nil {
+		me.MessageMaxBytes = other.MessageMaxBytes
+	}
+	for k, v := range other.Properties {
+		if me.Properties == nil {
+			me.Properties = make(map[string]string)
+		}
+		me.Properties[k] = v
 	}
 }
 
 func (me *KafkaConfig) AsProperties() string {
 	var b strings.Builder
-	if me.AutoCreateTopics {
-		b.WriteString(fmt.Sprintf("auto.create.topics.enable=%v\n", me.AutoCreateTopics))
+	if me.AutoCreateTopics != nil {
+		b.WriteString(fmt.Sprintf("auto.create.topics.enable=%v\n", *me.AutoCreateTopics))
+	}
+	if me.MinInsyncReplicas != nil {
+		b.WriteString(fmt.Sprintf("min.insync.replicas=%d\n", *me.MinInsyncReplicas))
+	}
+	if me.LogRetentionBytes != nil {
+		b.WriteString(fmt.Sprintf("log.retention.bytes=%d\n", *me.LogRetentionBytes))
 	}
-	if me.MinInsyncReplicas != -1 {
-		b.WriteString(fmt.Sprintf("min.insync.replicas=%d\n", me.MinInsyncReplicas))
+	if me.LogRetentionMs != nil {
+		b.WriteString(fmt.Sprintf("log.retention.ms=%d\n", *me.LogRetentionMs))
 	}
-	if me.LogRetentionBytes != -1 {
-		b.WriteString(fmt.Sprintf("log.retention.bytes=%d\n", me.LogRetentionBytes))
+	if me.LogSegmentBytes != nil {
+		b.WriteString(fmt.Sprintf("log.segment.bytes=%d\n", *me.LogSegmentBytes))
 	}
-	if me.LogRetentionMs != -1 {
-		b.WriteString(fmt.Sprintf("log.retention.ms=%d\n", me.LogRetentionMs))
+	if me.NetworkThreads != nil {
+		b.WriteString(fmt.Sprintf("num.network.threads=%d\n", *me.NetworkThreads))
 	}
-	if me.LogSegmentBytes != -1 {
-		b.WriteString(fmt.Sprintf("log.segment.bytes=%d\n", me.LogSegmentBytes))
+	if me.IOThreads != nil {
+		b.WriteString(fmt.Sprintf("num.io.threads=%d\n", *me.IOThreads))
 	}
-	if me.NetworkThreads != -1 {
-		b.WriteString(fmt.Sprintf("num.network.threads=%d\n", me.NetworkThreads))
+	if me.MessageMaxBytes != nil {
+		b.WriteString(fmt.Sprintf("message.max.bytes=%d\n", *me.MessageMaxBytes))
 	}
-	if me.IOThreads != -1 {
-		b.WriteString(fmt.Sprintf("num.io.threads=%d\n", me.IOThreads))
+	keys := make([]string, 0, len(me.Properties))
+	for k := range me.Properties {
+		keys = append(keys, k)
 	}
-	if me.MessageMaxBytes != -1 {
-		b.WriteString(fmt.Sprintf("message.max.bytes=%d\n", me.MessageMaxBytes))
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf("%s=%s\n", k, me.Properties[k]))
 	}
 	return b.String()
-
 }
 
-func (api *API) ReadConfig(instanceId int64) (*KafkaConfig, error) {
+func (api *API) ReadConfig(ctx context.Context, instanceId int64) (*KafkaConfig, error) {
 	var (
 		data   []Hash
 		failed APIError
 	)
 	path := fmt.Sprintf("/api/instances/%d/config/kafka", instanceId)
-	resp, err := api.client.New().Get(path).Receive(&data, &failed)
+	resp, err := api.request(ctx).Get(path).Receive(&data, &failed)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("instance with id %d %w", instanceId, ErrNotFound)
+	}
 	if resp.StatusCode != 200 {
 		return nil, failed
 	}
 	cfg := NewKafkaConfig()
 	for _, r := range data {
-		switch r["name"] {
-		case "auto.create.topics.enable":
-			cfg.AutoCreateTopics = r["value"].(bool)
-		case "num.io.threads":
-			cfg.IOThreads = int64(r["value"].(float64))
-		case "num.network.threads":
-			cfg.NetworkThreads = int64(r["value"].(float64))
-		case "log.retention.ms":
-			cfg.LogRetentionMs = int64(r["value"].(float64))
-		case "log.retention.bytes":
-			cfg.LogRetentionBytes = int64(r["value"].(float64))
-		case "log.segment.bytes":
-			cfg.LogSegmentBytes = int64(r["value"].(float64))
-		case "min.insync.replicas":
-			cfg.MinInsyncReplicas = int64(r["value"].(float64))
-		case "message.max.bytes":
-			cfg.MessageMaxBytes = int64(r["value"].(float64))
-		default:
-			return nil, fmt.Errorf("Unhandled config value %s", r["name"])
+		name, _ := r["name"].(string)
+		if err := cfg.set(name, r["value"]); err != nil {
+			return nil, err
 		}
 	}
 	return cfg, nil
 }
 
-func (api *API) WriteConfig(instanceId int64, config *KafkaConfig) error {
+func (api *API) WriteConfig(ctx context.Context, instanceId int64, config *KafkaConfig) error {
 	var failed APIError
 	path := fmt.Sprintf("/api/instances/%d/config/kafka", instanceId)
 	body := strings.NewReader(config.AsProperties())
-	resp, err := api.client.New().Post(path).Body(body).Receive(nil, &failed)
+	// The write merges the keys into the config, sending the same keys and
+	// values again changes nothing, so it is safe to retry.
+	resp, err := api.request(idempotent(ctx)).Post(path).Body(body).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
@@ -111,3 +247,109 @@ func (api *API) WriteConfig(instanceId int64, config *KafkaConfig) error {
 	}
 	return nil
 }
+
+// Get returns a setting as Kafka would print it, and whether it is set.
+func (me *KafkaConfig) Get(name string) (string, bool) {
+	var i *int64
+	switch name {
+	case "auto.create.topics.enable":
+		if me.AutoCreateTopics == nil {
+			return "", false
+		}
+		return strconv.FormatBool(*me.AutoCreateTopics), true
+	case "min.insync.replicas":
+		i = me.MinInsyncReplicas
+	case "log.retention.bytes":
+		i = me.LogRetentionBytes
+	case "log.retention.ms":
+		i = me.LogRetentionMs
+	case "log.segment.bytes":
+		i = me.LogSegmentBytes
+	case "num.network.threads":
+		i = me.NetworkThreads
+	case "num.io.threads":
+		i = me.IOThreads
+	case "message.max.bytes":
+		i = me.MessageMaxBytes
+	default:
+		v, ok := me.Properties[name]
+		return v, ok
+	}
+	if i == nil {
+		return "", false
+	}
+	return strconv.FormatInt(*i, 10), true
+}
+
+// set stores a value read from the server. Known keys are parsed into their
+// typed field, anything else is kept as a string in Properties.
+func (me *KafkaConfig) set(name string, value interface{}) error {
+	var (
+		i   int64
+		err error
+	)
+	switch name {
+	case "auto.create.topics.enable":
+		var b bool
+		if b, err = parseBool(value); err == nil {
+			me.AutoCreateTopics = &b
+		}
+	case "min.insync.replicas":
+		if i, err = parseInt64(value); err == nil {
+			me.MinInsyncReplicas = &i
+		}
+	case "log.retention.bytes":
+		if i, err = parseInt64(value); err == nil {
+			me.LogRetentionBytes = &i
+		}
+	case "log.retention.ms":
+		if i, err = parseInt64(value); err == nil {
+			me.LogRetentionMs = &i
+		}
+	case "log.segment.bytes":
+		if i, err = parseInt64(value); err == nil {
+			me.LogSegmentBytes = &i
+		}
+	case "num.network.threads":
+		if i, err = parseInt64(value); err == nil {
+			me.NetworkThreads = &i
+		}
+	case "num.io.threads":
+		if i, err = parseInt64(value); err == nil {
+			me.IOThreads = &i
+		}
+	case "message.max.bytes":
+		if i, err = parseInt64(value); err == nil {
+			me.MessageMaxBytes = &i
+		}
+	default:
+		if me.Properties == nil {
+			me.Properties = make(map[string]string)
+		}
+		me.Properties[name] = ValueString(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	return nil
+}
+
+func parseBool(v interface{}) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+	return false, fmt.Errorf("expected a boolean, got %v", v)
+}
+
+func parseInt64(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("expected a number, got %v", v)
+}
@@ -0,0 +1,128 @@
+package api
+
+import "testing"
+
+func TestKafkaConfigAsProperties(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *KafkaConfig
+		want   string
+	}{
+		{
+			name:   "empty",
+			config: NewKafkaConfig(),
+			want:   "",
+		},
+		{
+			name: "false and -1 are written",
+			config: &KafkaConfig{
+				AutoCreateTopics:  Bool(false),
+				LogRetentionBytes: Int64(-1),
+			},
+			want: "auto.create.topics.enable=false\nlog.retention.bytes=-1\n",
+		},
+		{
+			name: "all keys",
+			config: &KafkaConfig{
+				AutoCreateTopics:  Bool(true),
+				MinInsyncReplicas: Int64(2),
+				LogRetentionBytes: Int64(1000),
+				LogRetentionMs:    Int64(3600000),
+				LogSegmentBytes:   Int64(100000),
+				NetworkThreads:    Int64(4),
+				IOThreads:         Int64(10),
+				MessageMaxBytes:   Int64(2097152),
+			},
+			want: "auto.create.topics.enable=true\n" +
+				"min.insync.replicas=2\n" +
+				"log.retention.bytes=1000\n" +
+				"log.retention.ms=3600000\n" +
+				"log.segment.bytes=100000\n" +
+				"num.network.threads=4\n" +
+				"num.io.threads=10\n" +
+				"message.max.bytes=2097152\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.AsProperties(); got != tt.want {
+				t.Errorf("AsProperties() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKafkaConfigResetToDefaults(t *testing.T) {
+	config := &KafkaConfig{
+		AutoCreateTopics: Bool(false),
+		LogRetentionMs:   Int64(1000),
+		IOThreads:        Int64(16),
+		Properties:       map[string]string{"log.cleaner.threads": "2", "custom.setting": "x"},
+	}
+	want := "auto.create.topics.enable=true\nlog.retention.ms=604800000\nnum.io.threads=8\nlog.cleaner.threads=1\n"
+	if got := config.ResetToDefaults().AsProperties(); got != want {
+		t.Errorf("ResetToDefaults().AsProperties() = %q, want %q", got, want)
+	}
+	unknown := &KafkaConfig{Properties: map[string]string{"custom.setting": "x"}}
+	if reset := unknown.ResetToDefaults(); !reset.IsEmpty() {
+		t.Errorf("ResetToDefaults() of unknown properties = %q, want empty", reset.AsProperties())
+	}
+}
+
+func TestKafkaConfigMerge(t *testing.T) {
+	config := &KafkaConfig{
+		AutoCreateTopics: Bool(true),
+		IOThreads:        Int64(8),
+	}
+	config.Merge(&KafkaConfig{IOThreads: Int64(12), MessageMaxBytes: Int64(100)})
+	want := "auto.create.topics.enable=true\nnum.io.threads=12\nmessage.max.bytes=100\n"
+	if got := config.AsProperties(); got != want {
+		t.Errorf("Merge().AsProperties() = %q, want %q", got, want)
+	}
+}
+
+func TestKafkaConfigSet(t *testing.T) {
+	config := NewKafkaConfig()
+	values := map[string]interface{}{
+		"auto.create.topics.enable": "false",
+		"num.io.threads":            float64(8),
+		"log.retention.ms":          "604800000",
+		"log.cleaner.threads":       float64(2),
+		"compression.type":          "producer",
+	}
+	for k, v := range values {
+		if err := config.set(k, v); err != nil {
+			t.Fatalf("set(%q) failed: %v", k, err)
+		}
+	}
+	want := "auto.create.topics.enable=false\n" +
+		"log.retention.ms=604800000\n" +
+		"num.io.threads=8\n" +
+		"compression.type=producer\n" +
+		"log.cleaner.threads=2\n"
+	if got := config.AsProperties(); got != want {
+		t.Errorf("AsProperties() = %q, want %q", got, want)
+	}
+	if err := config.set("num.io.threads", "many"); err == nil {
+		t.Error("set() with a malformed number should fail")
+	}
+}
+
+func TestTopicDefaults(t *testing.T) {
+	config := DefaultKafkaConfig()
+	config.LogRetentionMs = Int64(3600000)
+	config.Properties = map[string]string{"log.cleanup.policy": "compact"}
+
+	defaults := TopicDefaults(config.Get)
+	want := map[string]string{
+		"retention.ms":        "3600000",
+		"cleanup.policy":      "compact",
+		"segment.bytes":       "1073741824",
+		"delete.retention.ms": "86400000",
+	}
+	for k, v := range want {
+		if defaults[k] != v {
+			t.Errorf("default of %s = %q, want %q", k, defaults[k], v)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListKafkaVersions returns the Kafka versions new instances can run and
+// existing ones can be upgraded to, oldest first.
+func (api *API) ListKafkaVersions(ctx context.Context) ([]string, error) {
+	var (
+		data   []string
+		failed APIError
+	)
+	response, err := api.request(ctx).Get("/api/kafka/versions").Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == 401 {
+		return nil, fmt.Errorf("Authentication error: %s", "invalid API key used")
+	}
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to list Kafka versions: %s", failed.Error())
+	}
+	return data, nil
+}
+
+// upgradeKafka starts a rolling upgrade of the instance to version. The
+// brokers restart one at a time, callers should wait until the instance is
+// ready again.
+func (api *API) upgradeKafka(ctx context.Context, id int64, version string) error {
+	var failed APIError
+	path := fmt.Sprintf("api/instances/%d/actions/upgrade-kafka", id)
+	body := map[string]string{"version": version}
+	response, err := api.request(ctx).Put(path).BodyJSON(body).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode == 400 {
+		return failed
+	}
+	if response.StatusCode == 401 {
+		return fmt.Errorf("Authentication error: %s", "invalid API key used")
+	}
+	if response.StatusCode != 200 {
+		return fmt.Errorf("upgrade Kafka failed: %s", failed.Error())
+	}
+	return nil
+}
+
+// CompareKafkaVersions compares two X.Y.Z versions part by part and returns
+// -1, 0 or 1 when a is older than, equal to or newer than b. Parts that are
+// not numbers compare as zero.
+func CompareKafkaVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for n := 0; n < len(as) || n < len(bs); n++ {
+		x, y := versionPart(as, n), versionPart(bs, n)
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionPart(parts []string, n int) int {
+	if n >= len(parts) {
+		return 0
+	}
+	v, _ := strconv.Atoi(parts[n])
+	return v
+}
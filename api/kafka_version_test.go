@@ -0,0 +1,21 @@
+package api
+
+import "testing"
+
+func TestCompareKafkaVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "3.5.1", b: "3.5.1", want: 0},
+		{a: "3.4.1", b: "3.5.1", want: -1},
+		{a: "3.10.0", b: "3.9.2", want: 1},
+		{a: "2.8.2", b: "3.0.0", want: -1},
+		{a: "3.5", b: "3.5.0", want: 0},
+	}
+	for _, tt := range tests {
+		if got := CompareKafkaVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareKafkaVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Quota limits the clients of a user, of a client ID, or of a client ID of a
+// user. Unset limits do not apply.
+type Quota struct {
+	User              string   `json:"user,omitempty"`
+	ClientId          string   `json:"client_id,omitempty"`
+	ProducerByteRate  *int64   `json:"producer_byte_rate,omitempty"`
+	ConsumerByteRate  *int64   `json:"consumer_byte_rate,omitempty"`
+	RequestPercentage *float64 `json:"request_percentage,omitempty"`
+}
+
+// Float64 returns a pointer to v, for request percentages.
+func Float64(v float64) *float64 {
+	return &v
+}
+
+// quotaPath returns the path of the quota of user and clientId, either of
+// which may be empty.
+func quotaPath(instanceId int64, user, clientId string) string {
+	query := url.Values{}
+	if user != "" {
+		query.Set("user", user)
+	}
+	if clientId != "" {
+		query.Set("client_id", clientId)
+	}
+	return fmt.Sprintf("/api/instances/%d/quotas?%s", instanceId, query.Encode())
+}
+
+// describeQuota names the quota of user and clientId in errors.
+func describeQuota(user, clientId string) string {
+	switch {
+	case user == "":
+		return fmt.Sprintf("quota for client ID %s", clientId)
+	case clientId == "":
+		return fmt.Sprintf("quota for user %s", user)
+	default:
+		return fmt.Sprintf("quota for user %s and client ID %s", user, clientId)
+	}
+}
+
+func (api *API) ReadQuota(ctx context.Context, instanceId int64, user, clientId string) (*Quota, error) {
+	var (
+		data   []Quota
+		failed APIError
+	)
+	path := fmt.Sprintf("/api/instances/%d/quotas", instanceId)
+	resp, err := api.request(ctx).Get(path).Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("instance with id %d %w", instanceId, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return nil, failed
+	}
+	for _, v := range data {
+		if v.User == user && v.ClientId == clientId {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %w", describeQuota(user, clientId), ErrNotFound)
+}
+
+func (api *API) CreateQuota(ctx context.Context, instanceId int64, quota Quota) error {
+	var failed APIError
+	path := fmt.Sprintf("/api/instances/%d/quotas", instanceId)
+	resp, err := api.request(ctx).Post(path).BodyJSON(quota).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 201 {
+		return failed
+	}
+	return nil
+}
+
+// UpdateQuota replaces the limits of an existing quota.
+func (api *API) UpdateQuota(ctx context.Context, instanceId int64, quota Quota) error {
+	var failed APIError
+	path := quotaPath(instanceId, quota.User, quota.ClientId)
+	resp, err := api.request(ctx).Put(path).BodyJSON(quota).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == 404 {
+		return fmt.Errorf("%s %w", describeQuota(quota.User, quota.ClientId), ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return failed
+	}
+	return nil
+}
+
+func (api *API) DeleteQuota(ctx context.Context, instanceId int64, user, clientId string) error {
+	var failed APIError
+	path := quotaPath(instanceId, user, clientId)
+	resp, err := api.request(ctx).Delete(path).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == 404 {
+		return fmt.Errorf("%s %w", describeQuota(user, clientId), ErrNotFound)
+	}
+	if resp.StatusCode != 204 {
+		return failed
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	transport := &retryTransport{maxWait: 10 * time.Second}
+	tests := []struct {
+		name     string
+		attempt  int
+		header   string
+		min, max time.Duration
+	}{
+		{name: "first attempt", attempt: 0, min: retryMinWait / 2, max: retryMinWait},
+		{name: "doubles", attempt: 2, min: 2 * time.Second, max: 4 * time.Second},
+		{name: "capped", attempt: 10, min: 5 * time.Second, max: 10 * time.Second},
+		{name: "shift overflow is capped", attempt: 80, min: 5 * time.Second, max: 10 * time.Second},
+		{name: "retry-after wins", attempt: 0, header: "3", min: 3 * time.Second, max: 3 * time.Second},
+		{name: "retry-after is capped", attempt: 0, header: "120", min: 10 * time.Second, max: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			for i := 0; i < 20; i++ {
+				if got := transport.backoff(tt.attempt, resp); got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		ok       bool
+		min, max time.Duration
+	}{
+		{name: "empty", value: ""},
+		{name: "seconds", value: "7", ok: true, min: 7 * time.Second, max: 7 * time.Second},
+		{name: "negative", value: "-1"},
+		{name: "garbage", value: "soon"},
+		{
+			name:  "http date",
+			value: time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat),
+			ok:    true, min: 28 * time.Second, max: 30 * time.Second,
+		},
+		{name: "date in the past", value: "Mon, 02 Jan 2006 15:04:05 GMT", ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retryAfter(tt.value)
+			if ok != tt.ok || got < tt.min || got > tt.max {
+				t.Errorf("retryAfter(%q) = %s, %t, want between %s and %s, %t", tt.value, got, ok, tt.min, tt.max, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCanRetry(t *testing.T) {
+	newRequest := func(ctx context.Context, method string, body io.Reader) *http.Request {
+		req, err := http.NewRequestWithContext(ctx, method, "http://localhost/api", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	ctx := context.Background()
+	unbuffered := newRequest(ctx, http.MethodPut, nil)
+	unbuffered.Body = io.NopCloser(strings.NewReader("x"))
+	tests := []struct {
+		name string
+		req  *http.Request
+		want bool
+	}{
+		{name: "get", req: newRequest(ctx, http.MethodGet, nil), want: true},
+		{name: "put", req: newRequest(ctx, http.MethodPut, strings.NewReader("x")), want: true},
+		{name: "delete", req: newRequest(ctx, http.MethodDelete, nil), want: true},
+		{name: "post", req: newRequest(ctx, http.MethodPost, strings.NewReader("x")), want: false},
+		{name: "idempotent post", req: newRequest(idempotent(ctx), http.MethodPost, strings.NewReader("x")), want: true},
+		{name: "body that cannot be replayed", req: unbuffered, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canRetry(tt.req); got != tt.want {
+				t.Errorf("canRetry() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "connection refused", err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), want: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: true},
+		{name: "canceled", err: fmt.Errorf("do: %w", context.Canceled), want: false},
+		{name: "other error", err: fmt.Errorf("bad certificate"), want: false},
+		{name: "too many requests", status: http.StatusTooManyRequests, want: true},
+		{name: "bad gateway", status: http.StatusBadGateway, want: true},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, want: true},
+		{name: "internal server error", status: http.StatusInternalServerError, want: false},
+		{name: "not found", status: http.StatusNotFound, want: false},
+		{name: "ok", status: http.StatusOK, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			if got := shouldRetry(resp, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// roundTripFunc sends requests with a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRetryTransportLeavesRequestAlone(t *testing.T) {
+	var bodies []string
+	transport := &retryTransport{
+		next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) < 3 {
+				return nil, syscall.ECONNRESET
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		maxRetries: 3,
+		maxWait:    time.Millisecond,
+	}
+	req, err := http.NewRequest(http.MethodPut, "http://localhost/api", strings.NewReader("config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := req.Body
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 3 || bodies[1] != "config" || bodies[2] != "config" {
+		t.Errorf("attempts sent bodies %q, want the full body three times", bodies)
+	}
+	if req.Body != body {
+		t.Error("the caller's request body was replaced")
+	}
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dghubble/sling"
+)
+
+// SchemaTypes are the schema formats the Schema Registry accepts. Schemas
+// without a type are Avro.
+var SchemaTypes = []string{"AVRO", "JSON", "PROTOBUF"}
+
+// CompatibilityLevels are the compatibility levels of the Schema Registry.
+var CompatibilityLevels = []string{"BACKWARD", "BACKWARD_TRANSITIVE", "FORWARD", "FORWARD_TRANSITIVE",
+	"FULL", "FULL_TRANSITIVE", "NONE"}
+
+// DefaultCompatibilityLevel is the global compatibility level of a new
+// Schema Registry.
+const DefaultCompatibilityLevel = "BACKWARD"
+
+// SchemaReference points at a schema registered under another subject, e.g.
+// an Avro record type or an imported Protobuf file.
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int64  `json:"version"`
+}
+
+// Schema is a version of a subject.
+type Schema struct {
+	Subject    string            `json:"subject,omitempty"`
+	Id         int64             `json:"id,omitempty"`
+	Version    int64             `json:"version,omitempty"`
+	Schema     string            `json:"schema"`
+	SchemaType string            `json:"schemaType,omitempty"`
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+// SchemaRegistryError is the error body of the Schema Registry.
+type SchemaRegistryError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+func (e SchemaRegistryError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("schema registry error %d", e.ErrorCode)
+}
+
+// SchemaRegistry talks to the Schema Registry add-on of an instance.
+type SchemaRegistry struct {
+	client *sling.Sling
+	doer   sling.Doer
+}
+
+// SchemaRegistry returns a client for the Schema Registry of the instance,
+// which authenticates with the instance credentials.
+func (api *API) SchemaRegistry(ctx context.Context, instanceId int64) (*SchemaRegistry, error) {
+	instance, err := api.readInstance(ctx, instanceId)
+	if err != nil {
+		return nil, err
+	}
+	if instance.SchemaRegistryUrl == "" {
+		return nil, fmt.Errorf("instance %d has no Schema Registry, enable the add-on first", instanceId)
+	}
+	base := strings.TrimSuffix(instance.SchemaRegistryUrl, "/") + "/"
+	return &SchemaRegistry{
+		client: sling.New().
+			Base(base).
+			SetBasicAuth(instance.Username, instance.Password).
+			Set("User-Agent", "terraform").
+			Set("Accept", "application/vnd.schemaregistry.v1+json"),
+		doer: api.doer,
+	}, nil
+}
+
+func (r *SchemaRegistry) request(ctx context.Context) *sling.Sling {
+	return r.client.New().Doer(contextDoer{ctx: ctx, doer: r.doer})
+}
+
+func subjectPath(subject string, elem ...string) string {
+	return strings.Join(append([]string{"subjects", url.PathEscape(subject)}, elem...), "/")
+}
+
+// RegisterSchema registers the schema under the subject and returns it with
+// its id and version. A schema that is already registered under the subject
+// keeps its version.
+func (r *SchemaRegistry) RegisterSchema(ctx context.Context, subject string, schema Schema) (*Schema, error) {
+	var (
+		data   Schema
+		failed SchemaRegistryError
+	)
+	schema.Subject, schema.Id, schema.Version = "", 0, 0
+	// Registering the same schema again is harmless, so retry it.
+	resp, err := r.request(idempotent(ctx)).Post(subjectPath(subject, "versions")).BodyJSON(schema).Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, failed
+	}
+	// The version is only known by looking the schema up.
+	data = Schema{}
+	resp, err = r.request(idempotent(ctx)).Post(subjectPath(subject)).BodyJSON(schema).Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, failed
+	}
+	return &data, nil
+}
+
+// ReadSchema returns a version of the subject, or the latest version when
+// version is zero.
+func (r *SchemaRegistry) ReadSchema(ctx context.Context, subject string, version int64) (*Schema, error) {
+	var (
+		data   Schema
+		failed SchemaRegistryError
+	)
+	v := "latest"
+	if version > 0 {
+		v = fmt.Sprint(version)
+	}
+	resp, err := r.request(ctx).Get(subjectPath(subject, "versions", v)).Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("subject %s version %s %w", subject, v, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return nil, failed
+	}
+	return &data, nil
+}
+
+// DeleteSubject deletes all versions of the subject. A soft deleted subject
+// can be registered again with its old schema ids, permanent also removes
+// the schemas.
+func (r *SchemaRegistry) DeleteSubject(ctx context.Context, subject string, permanent bool) error {
+	var failed SchemaRegistryError
+	resp, err := r.request(ctx).Delete(subjectPath(subject)).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == 404 {
+		return fmt.Errorf("subject %s %w", subject, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return failed
+	}
+	if !permanent {
+		return nil
+	}
+	resp, err = r.request(ctx).Delete(subjectPath(subject)+"?permanent=true").Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return failed
+	}
+	return nil
+}
+
+func configPath(subject string) string {
+	if subject == "" {
+		return "config"
+	}
+	return "config/" + url.PathEscape(subject)
+}
+
+// ReadCompatibility returns the compatibility level of the subject, or the
+// global level when subject is empty. A subject without a level of its own
+// is not found.
+func (r *SchemaRegistry) ReadCompatibility(ctx context.Context, subject string) (string, error) {
+	var (
+		data struct {
+			CompatibilityLevel string `json:"compatibilityLevel"`
+		}
+		failed SchemaRegistryError
+	)
+	resp, err := r.request(ctx).Get(configPath(subject)).Receive(&data, &failed)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == 404 {
+		return "", fmt.Errorf("compatibility level of subject %s %w", subject, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return "", failed
+	}
+	return data.CompatibilityLevel, nil
+}
+
+// SetCompatibility sets the compatibility level of the subject, or the
+// global level when subject is empty.
+func (r *SchemaRegistry) SetCompatibility(ctx context.Context, subject, level string) error {
+	var failed SchemaRegistryError
+	body := map[string]string{"compatibility": level}
+	resp, err := r.request(ctx).Put(configPath(subject)).BodyJSON(body).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return failed
+	}
+	return nil
+}
+
+// ResetCompatibility removes the compatibility level of the subject, which
+// then follows the global level.
+func (r *SchemaRegistry) ResetCompatibility(ctx context.Context, subject string) error {
+	var failed SchemaRegistryError
+	resp, err := r.request(ctx).Delete(configPath(subject)).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == 404 {
+		return fmt.Errorf("compatibility level of subject %s %w", subject, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return failed
+	}
+	return nil
+}
@@ -1,8 +1,8 @@
 package api
 
 import (
+	"context"
 	"fmt"
-	"time"
 )
 
 type Topic struct {
@@ -13,34 +13,72 @@ type Topic struct {
 	Config     Hash   `json:"config,omitempty"`
 }
 
+// UpdateTopicRequest changes a topic. The config is merged into the topic
+// config, a key with a nil value is reset to its default.
 type UpdateTopicRequest struct {
 	Partitions int64 `json:"partitions"`
 	Config     Hash  `json:"config,omitempty"`
 }
 
-func (api *API) waitUntilTopicReady(instanceId int64, topic string) error {
+// topicSettingDefaults maps topic settings to the broker setting that holds
+// their default on the instance, and to the Kafka default used when the
+// broker does not report it.
+var topicSettingDefaults = map[string]struct{ broker, kafka string }{
+	"cleanup.policy":         {"log.cleanup.policy", "delete"},
+	"min.insync.replicas":    {"min.insync.replicas", "1"},
+	"retention.bytes":        {"log.retention.bytes", "-1"},
+	"retention.ms":           {"log.retention.ms", "604800000"},
+	"delete.retention.ms":    {"log.cleaner.delete.retention.ms", "86400000"},
+	"segment.bytes":          {"log.segment.bytes", "1073741824"},
+	"compression.type":       {"compression.type", "producer"},
+	"max.message.bytes":      {"message.max.bytes", "1048588"},
+	"message.timestamp.type": {"log.message.timestamp.type", "CreateTime"},
+	"min.compaction.lag.ms":  {"log.cleaner.min.compaction.lag.ms", "0"},
+}
+
+// TopicDefaults returns the values topics get for the settings they are
+// created without, looking up the broker settings of the instance with
+// broker. Settings without a known default are left out.
+func TopicDefaults(broker func(name string) (string, bool)) map[string]string {
+	defaults := make(map[string]string, len(topicSettingDefaults))
+	for name, d := range topicSettingDefaults {
+		if v, ok := broker(d.broker); ok {
+			defaults[name] = v
+		} else {
+			defaults[name] = d.kafka
+		}
+	}
+	return defaults
+}
+
+func (api *API) waitUntilTopicReady(ctx context.Context, instanceId int64, topic string) error {
 	for {
-		time.Sleep(5 * time.Second)
-		topic, err := api.readTopic(instanceId, topic)
+		if err := sleep(ctx, api.topicPoll); err != nil {
+			return fmt.Errorf("waiting for topic %s to be ready: %w", topic, err)
+		}
+		t, err := api.readTopic(ctx, instanceId, topic)
 		if err != nil {
 			return err
 		}
-		if topic.Status == "ready" {
+		if t.Status == "ready" {
 			return nil
 		}
 	}
 }
 
-func (api *API) readTopics(instanceId int64) ([]Topic, error) {
+func (api *API) readTopics(ctx context.Context, instanceId int64) ([]Topic, error) {
 	var (
 		data   []Topic
 		failed APIError
 	)
 	path := fmt.Sprintf("/api/instances/%d/topics", instanceId)
-	resp, err := api.client.New().Get(path).Receive(&data, &failed)
+	resp, err := api.request(ctx).Get(path).Receive(&data, &failed)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("instance with id %d %w", instanceId, ErrNotFound)
+	}
 	if resp.StatusCode != 200 {
 		return nil, failed
 	}
@@ -48,8 +86,8 @@ func (api *API) readTopics(instanceId int64) ([]Topic, error) {
 
 }
 
-func (api *API) readTopic(instanceId int64, name string) (*Topic, error) {
-	topics, err := api.readTopics(instanceId)
+func (api *API) readTopic(ctx context.Context, instanceId int64, name string) (*Topic, error) {
+	topics, err := api.readTopics(ctx, instanceId)
 	if err != nil {
 		return nil, err
 	}
@@ -58,46 +96,46 @@ func (api *API) readTopic(instanceId int64, name string) (*Topic, error) {
 			return &v, nil
 		}
 	}
-	return nil, fmt.Errorf("topic %s not found", name)
+	return nil, fmt.Errorf("topic %s %w", name, ErrNotFound)
 }
 
-func (api *API) ReadTopic(instanceId int64, name string) (*Topic, error) {
-	return api.readTopic(instanceId, name)
+func (api *API) ReadTopic(ctx context.Context, instanceId int64, name string) (*Topic, error) {
+	return api.readTopic(ctx, instanceId, name)
 }
 
-func (api *API) CreateTopic(instanceId int64, params Topic) error {
+func (api *API) CreateTopic(ctx context.Context, instanceId int64, params Topic) error {
 	var failed APIError
 	path := fmt.Sprintf("/api/instances/%d/topics", instanceId)
-	resp, err := api.client.New().Post(path).BodyJSON(params).Receive(nil, &failed)
+	resp, err := api.request(ctx).Post(path).BodyJSON(params).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
 	if resp.StatusCode != 201 {
 		return failed
 	}
-	if err := api.waitUntilTopicReady(instanceId, params.Name); err != nil {
+	if err := api.waitUntilTopicReady(ctx, instanceId, params.Name); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (api *API) UpdateTopic(instanceId int64, name string, params UpdateTopicRequest) error {
+func (api *API) UpdateTopic(ctx context.Context, instanceId int64, name string, params UpdateTopicRequest) error {
 	var failed APIError
 	path := fmt.Sprintf("/api/instances/%d/topics/%s", instanceId, name)
-	resp, err := api.client.New().Put(path).BodyJSON(params).Receive(nil, &failed)
+	resp, err := api.request(ctx).Put(path).BodyJSON(params).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
 	if resp.StatusCode != 200 {
 		return failed
 	}
-	return nil
+	return api.waitUntilTopicReady(ctx, instanceId, name)
 }
 
-func (api *API) DeleteTopic(instanceId int64, name string) error {
+func (api *API) DeleteTopic(ctx context.Context, instanceId int64, name string) error {
 	var failed APIError
 	path := fmt.Sprintf("/api/instances/%d/topics/%s", instanceId, name)
-	resp, err := api.client.New().Delete(path).Receive(nil, &failed)
+	resp, err := api.request(ctx).Delete(path).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
@@ -1,49 +1,92 @@
 package api
 
 import (
+	"context"
 	"fmt"
 )
 
 type User struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// Credentials are only sent when a user is created or its credentials
+	// are rotated, never when users are listed. Password is for sasl users,
+	// Certificate and PrivateKey for ssl users.
+	Password    string `json:"password,omitempty"`
+	Certificate string `json:"certificate,omitempty"`
+	PrivateKey  string `json:"private_key,omitempty"`
 }
 
-func (api *API) ReadUser(instanceId int64, name string) (*User, error) {
+func (api *API) ReadUser(ctx context.Context, instanceId int64, name string) (*User, error) {
 	var (
 		data   []User
 		failed APIError
 	)
 	path := fmt.Sprintf("/api/instances/%d/users", instanceId)
-	_, err := api.client.New().Get(path).Receive(&data, &failed)
+	resp, err := api.request(ctx).Get(path).Receive(&data, &failed)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("instance with id %d %w", instanceId, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return nil, failed
+	}
 	for _, v := range data {
 		if v.Name == name {
 			return &v, nil
 		}
 	}
-	return nil, fmt.Errorf("user %s not found", name)
+	return nil, fmt.Errorf("user %s %w", name, ErrNotFound)
 }
 
-func (api *API) CreateUser(instanceId int64, params User) error {
-	var failed APIError
+// CreateUser creates a user and returns it with its credentials. A sasl user
+// gets params.Password, or a generated one if it is empty.
+func (api *API) CreateUser(ctx context.Context, instanceId int64, params User) (*User, error) {
+	var (
+		data   User
+		failed APIError
+	)
 	path := fmt.Sprintf("/api/instances/%d/users", instanceId)
-	resp, err := api.client.New().Post(path).BodyJSON(params).Receive(nil, &failed)
+	resp, err := api.request(ctx).Post(path).BodyJSON(params).Receive(&data, &failed)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if resp.StatusCode != 201 {
-		return failed
+		return nil, failed
 	}
-	return nil
+	return &data, nil
+}
+
+// RotateUserCredentials replaces the credentials of a user and returns the
+// new ones. A sasl user gets password, or a generated one if it is empty.
+func (api *API) RotateUserCredentials(ctx context.Context, instanceId int64, name, password string) (*User, error) {
+	var (
+		data   User
+		failed APIError
+	)
+	path := fmt.Sprintf("/api/instances/%d/users/%s/credentials", instanceId, name)
+	body := map[string]string{}
+	if password != "" {
+		body["password"] = password
+	}
+	resp, err := api.request(ctx).Put(path).BodyJSON(body).Receive(&data, &failed)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("user %s %w", name, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return nil, failed
+	}
+	return &data, nil
 }
 
-func (api *API) DeleteUser(instanceId int64, name string) error {
+func (api *API) DeleteUser(ctx context.Context, instanceId int64, name string) error {
 	var failed APIError
 	path := fmt.Sprintf("/api/instances/%d/users/%s", instanceId, name)
-	resp, err := api.client.New().Delete(path).Receive(nil, &failed)
+	resp, err := api.request(ctx).Delete(path).Receive(nil, &failed)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+type CreateVPCRequest struct {
+	Name   string   `json:"name"`
+	Region string   `json:"region"`
+	Subnet string   `json:"subnet"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+type VPCResponse struct {
+	Id     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Region string   `json:"region"`
+	Subnet string   `json:"subnet"`
+	Tags   []string `json:"tags"`
+}
+
+func (api *API) CreateVPC(ctx context.Context, req CreateVPCRequest) (VPCResponse, error) {
+	var (
+		data   map[string]interface{}
+		failed APIError
+	)
+	response, err := api.request(ctx).Post("/api/vpcs").BodyJSON(req).Receive(&data, &failed)
+	if err != nil {
+		return VPCResponse{}, err
+	}
+	if response.StatusCode == 401 {
+		return VPCResponse{}, fmt.Errorf("Authentication error: %s", "invalid API key used")
+	}
+	if response.StatusCode == 400 {
+		return VPCResponse{}, fmt.Errorf("Validation error: %s", failed.Error())
+	}
+	if response.StatusCode != 200 {
+		return VPCResponse{}, fmt.Errorf("failed to create VPC: %s", failed.Error())
+	}
+	return api.ReadVPC(ctx, int64(data["id"].(float64)))
+}
+
+func (api *API) ReadVPC(ctx context.Context, id int64) (VPCResponse, error) {
+	var (
+		data   VPCResponse
+		failed APIError
+	)
+	path := fmt.Sprintf("/api/vpcs/%d", id)
+	response, err := api.request(ctx).Get(path).Receive(&data, &failed)
+	if err != nil {
+		return VPCResponse{}, err
+	}
+	if response.StatusCode == 404 {
+		return VPCResponse{}, fmt.Errorf("VPC with id %d %w", id, ErrNotFound)
+	}
+	if response.StatusCode != 200 {
+		return VPCResponse{}, fmt.Errorf("failed to fetch info about VPC: %s", failed.Error())
+	}
+	return data, nil
+}
+
+// DeleteVPC deletes a VPC. The API refuses while instances still run in it.
+func (api *API) DeleteVPC(ctx context.Context, id int64) error {
+	var failed APIError
+	path := fmt.Sprintf("/api/vpcs/%d", id)
+	response, err := api.request(ctx).Delete(path).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != 204 {
+		return fmt.Errorf("failed to delete VPC: %s", failed.Error())
+	}
+	return nil
+}
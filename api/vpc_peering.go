@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Peering statuses. A peering is pending while CloudKarafka sets it up, and
+// pending acceptance while it waits for the peer side, e.g. for the owner
+// of an AWS VPC to accept the request.
+const (
+	VPCPeeringPending           = "pending"
+	VPCPeeringPendingAcceptance = "pending-acceptance"
+	VPCPeeringActive            = "active"
+	VPCPeeringFailed            = "failed"
+)
+
+// VPCPeeringInfo describes the CloudKarafka side of a VPC, for setting up the
+// peer side. AccountId is the AWS account, GCP project or Azure subscription
+// and NetworkId the AWS VPC ID, GCP network URI or Azure virtual network ID.
+type VPCPeeringInfo struct {
+	Region    string `json:"region"`
+	Subnet    string `json:"subnet"`
+	AccountId string `json:"account_id"`
+	NetworkId string `json:"network_id"`
+}
+
+type VPCPeering struct {
+	Id            string `json:"id,omitempty"`
+	PeerNetworkId string `json:"peer_network_id"`
+	PeerAccountId string `json:"peer_account_id,omitempty"`
+	PeerRegion    string `json:"peer_region,omitempty"`
+	PeerSubnet    string `json:"peer_subnet,omitempty"`
+	Status        string `json:"status,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+}
+
+func (api *API) ReadVPCPeeringInfo(ctx context.Context, vpcId int64) (VPCPeeringInfo, error) {
+	var (
+		data   VPCPeeringInfo
+		failed APIError
+	)
+	path := fmt.Sprintf("/api/vpcs/%d/peering-info", vpcId)
+	response, err := api.request(ctx).Get(path).Receive(&data, &failed)
+	if err != nil {
+		return VPCPeeringInfo{}, err
+	}
+	if response.StatusCode == 404 {
+		return VPCPeeringInfo{}, fmt.Errorf("VPC with id %d %w", vpcId, ErrNotFound)
+	}
+	if response.StatusCode != 200 {
+		return VPCPeeringInfo{}, failed
+	}
+	return data, nil
+}
+
+// RequestVPCPeering asks CloudKarafka to peer the VPC with another network
+// and waits until the peering is active or waits for the peer side.
+func (api *API) RequestVPCPeering(ctx context.Context, vpcId int64, req VPCPeering) (VPCPeering, error) {
+	var (
+		data   VPCPeering
+		failed APIError
+	)
+	path := fmt.Sprintf("/api/vpcs/%d/peerings", vpcId)
+	response, err := api.request(ctx).Post(path).BodyJSON(req).Receive(&data, &failed)
+	if err != nil {
+		return VPCPeering{}, err
+	}
+	if response.StatusCode != 201 {
+		return VPCPeering{}, failed
+	}
+	return api.waitUntilPeered(ctx, vpcId, data.Id)
+}
+
+// AcceptVPCPeering accepts a peering request made from the peer side and
+// waits until the peering is active.
+func (api *API) AcceptVPCPeering(ctx context.Context, vpcId int64, peeringId string) (VPCPeering, error) {
+	var failed APIError
+	path := fmt.Sprintf("/api/vpcs/%d/peerings/%s/accept", vpcId, peeringId)
+	response, err := api.request(ctx).Put(path).Receive(nil, &failed)
+	if err != nil {
+		return VPCPeering{}, err
+	}
+	if response.StatusCode == 404 {
+		return VPCPeering{}, fmt.Errorf("peering request %s %w", peeringId, ErrNotFound)
+	}
+	if response.StatusCode != 200 {
+		return VPCPeering{}, failed
+	}
+	return api.waitUntilPeered(ctx, vpcId, peeringId)
+}
+
+func (api *API) ReadVPCPeering(ctx context.Context, vpcId int64, peeringId string) (VPCPeering, error) {
+	var (
+		data   VPCPeering
+		failed APIError
+	)
+	path := fmt.Sprintf("/api/vpcs/%d/peerings/%s", vpcId, peeringId)
+	response, err := api.request(ctx).Get(path).Receive(&data, &failed)
+	if err != nil {
+		return VPCPeering{}, err
+	}
+	if response.StatusCode == 404 {
+		return VPCPeering{}, fmt.Errorf("peering %s of VPC %d %w", peeringId, vpcId, ErrNotFound)
+	}
+	if response.StatusCode != 200 {
+		return VPCPeering{}, failed
+	}
+	return data, nil
+}
+
+func (api *API) DeleteVPCPeering(ctx context.Context, vpcId int64, peeringId string) error {
+	var failed APIError
+	path := fmt.Sprintf("/api/vpcs/%d/peerings/%s", vpcId, peeringId)
+	response, err := api.request(ctx).Delete(path).Receive(nil, &failed)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != 204 {
+		return failed
+	}
+	return nil
+}
+
+// waitUntilPeered polls the peering until it is no longer pending.
+func (api *API) waitUntilPeered(ctx context.Context, vpcId int64, peeringId string) (VPCPeering, error) {
+	for {
+		peering, err := api.ReadVPCPeering(ctx, vpcId, peeringId)
+		if err != nil {
+			return VPCPeering{}, err
+		}
+		switch peering.Status {
+		case VPCPeeringPending:
+		case VPCPeeringFailed:
+			return peering, fmt.Errorf("peering %s of VPC %d failed: %s", peeringId, vpcId, peering.StatusMessage)
+		default:
+			return peering, nil
+		}
+		tflog.Info(ctx, fmt.Sprintf("Waiting for peering %s of VPC %d", peeringId, vpcId))
+		if err := sleep(ctx, api.instancePoll); err != nil {
+			return VPCPeering{}, fmt.Errorf("waiting for peering %s of VPC %d: %w", peeringId, vpcId, err)
+		}
+	}
+}
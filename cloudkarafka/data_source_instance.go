@@ -0,0 +1,213 @@
+package cloudkarafka
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                     = &instanceDataSource{}
+	_ datasource.DataSourceWithConfigure        = &instanceDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &instanceDataSource{}
+)
+
+// NewInstanceDataSource is a helper function to simplify the provider implementation.
+func NewInstanceDataSource() datasource.DataSource {
+	return &instanceDataSource{}
+}
+
+// instanceDataSource is the data source implementation.
+type instanceDataSource struct {
+	client *api.API
+}
+
+type instanceDataSourceModel struct {
+	ID           types.Int64    `tfsdk:"id"`
+	Name         types.String   `tfsdk:"name"`
+	Tag          types.String   `tfsdk:"tag"`
+	Plan         types.String   `tfsdk:"plan"`
+	Region       types.String   `tfsdk:"region"`
+	Tags         []types.String `tfsdk:"tags"`
+	KafkaVersion types.String   `tfsdk:"kafka_version"`
+	VPCSubnet    types.String   `tfsdk:"vpc_subnet"`
+	VPCId        types.Int64    `tfsdk:"vpc_id"`
+	Brokers      types.List     `tfsdk:"brokers"`
+	Username     types.String   `tfsdk:"username"`
+	Password     types.String   `tfsdk:"password"`
+	ApiKey       types.String   `tfsdk:"apikey"`
+}
+
+// Metadata returns the data source type name.
+func (d *instanceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance"
+}
+
+// Schema defines the schema for the data source.
+func (d *instanceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Look up an instance by id, or by name and/or tag.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Description: "Instance ID.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of instance.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tag": schema.StringAttribute{
+				Description: "Only match instances with this tag.",
+				Optional:    true,
+			},
+			"plan": schema.StringAttribute{
+				Description: "Plan of the instance.",
+				Computed:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region of the instance.",
+				Computed:    true,
+			},
+			"tags": schema.ListAttribute{
+				Description: "Instance tags.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"kafka_version": schema.StringAttribute{
+				Description: "Apache Kafka version of the instance.",
+				Computed:    true,
+			},
+			"vpc_subnet": schema.StringAttribute{
+				Description: "Subnet for the VPC.",
+				Computed:    true,
+			},
+			"vpc_id": schema.Int64Attribute{
+				Description: "ID of the VPC.",
+				Computed:    true,
+			},
+			"brokers": schema.ListAttribute{
+				Description: "Broker connection strings, host:port.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"username": schema.StringAttribute{
+				Description: "Username for the default SASL user.",
+				Computed:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Password for the default SASL user.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"apikey": schema.StringAttribute{
+				Description: "API key for the instance.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (d *instanceDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.AtLeastOneOf(path.MatchRoot("id"), path.MatchRoot("name"), path.MatchRoot("tag")),
+		datasourcevalidator.Conflicting(path.MatchRoot("id"), path.MatchRoot("name")),
+		datasourcevalidator.Conflicting(path.MatchRoot("id"), path.MatchRoot("tag")),
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *instanceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.API)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *instanceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state instanceDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := state.ID.ValueInt64()
+	if state.ID.IsNull() {
+		instances, err := d.client.ListInstances(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to list instances", err.Error())
+			return
+		}
+		var matches []api.InstanceResponse
+		for _, i := range instances {
+			if !state.Name.IsNull() && i.Name != state.Name.ValueString() {
+				continue
+			}
+			if !state.Tag.IsNull() && !hasTags(i.Tags, state.Tag.ValueString()) {
+				continue
+			}
+			matches = append(matches, i)
+		}
+		if len(matches) != 1 {
+			resp.Diagnostics.AddError("Failed to find instance",
+				fmt.Sprintf("Expected exactly one instance matching name=%q tag=%q, found %d",
+					state.Name.ValueString(), state.Tag.ValueString(), len(matches)))
+			return
+		}
+		id = matches[0].Id
+	}
+
+	instance, err := d.client.ReadInstance(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read instance", err.Error())
+		return
+	}
+	var tags []types.String
+	for _, t := range instance.Tags {
+		tags = append(tags, types.StringValue(t))
+	}
+	brokers, diags := types.ListValueFrom(ctx, types.StringType, instance.Brokers())
+	resp.Diagnostics.Append(diags...)
+	state.ID = types.Int64Value(instance.Id)
+	state.Name = types.StringValue(instance.Name)
+	state.Plan = types.StringValue(instance.Plan)
+	state.Region = types.StringValue(instance.Region)
+	state.Tags = tags
+	state.KafkaVersion = types.StringValue(instance.KafkaVersion)
+	state.VPCSubnet = types.StringValue(instance.Vpc.Subnet)
+	state.VPCId = types.Int64Value(instance.Vpc.Id)
+	state.Brokers = brokers
+	state.Username = types.StringValue(instance.Username)
+	state.Password = types.StringValue(instance.Password)
+	state.ApiKey = types.StringValue(instance.ApiKey)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// hasTags reports whether all of the wanted tags are present in tags.
+func hasTags(tags []string, wanted ...string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, t := range tags {
+			if t == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
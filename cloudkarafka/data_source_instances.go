@@ -0,0 +1,134 @@
+package cloudkarafka
+
+import (
+	"context"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &instancesDataSource{}
+	_ datasource.DataSourceWithConfigure = &instancesDataSource{}
+)
+
+// NewInstancesDataSource is a helper function to simplify the provider implementation.
+func NewInstancesDataSource() datasource.DataSource {
+	return &instancesDataSource{}
+}
+
+// instancesDataSource is the data source implementation.
+type instancesDataSource struct {
+	client *api.API
+}
+
+type instancesDataSourceModel struct {
+	Tags      []types.String                `tfsdk:"tags"`
+	Instances []instancesDataSourceInstance `tfsdk:"instances"`
+}
+
+type instancesDataSourceInstance struct {
+	ID     types.Int64    `tfsdk:"id"`
+	Name   types.String   `tfsdk:"name"`
+	Plan   types.String   `tfsdk:"plan"`
+	Region types.String   `tfsdk:"region"`
+	Tags   []types.String `tfsdk:"tags"`
+}
+
+// Metadata returns the data source type name.
+func (d *instancesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instances"
+}
+
+// Schema defines the schema for the data source.
+func (d *instancesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List instances, optionally filtered by tags.",
+		Attributes: map[string]schema.Attribute{
+			"tags": schema.SetAttribute{
+				Description: "Only list instances that have all of these tags.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"instances": schema.ListNestedAttribute{
+				Description: "Matching instances.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Description: "Instance ID.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Name of instance.",
+							Computed:    true,
+						},
+						"plan": schema.StringAttribute{
+							Description: "Plan of the instance.",
+							Computed:    true,
+						},
+						"region": schema.StringAttribute{
+							Description: "Region of the instance.",
+							Computed:    true,
+						},
+						"tags": schema.ListAttribute{
+							Description: "Instance tags.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *instancesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.API)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *instancesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state instancesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instances, err := d.client.ListInstances(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list instances", err.Error())
+		return
+	}
+	var wanted []string
+	for _, t := range state.Tags {
+		wanted = append(wanted, t.ValueString())
+	}
+	state.Instances = []instancesDataSourceInstance{}
+	for _, i := range instances {
+		if !hasTags(i.Tags, wanted...) {
+			continue
+		}
+		var tags []types.String
+		for _, t := range i.Tags {
+			tags = append(tags, types.StringValue(t))
+		}
+		state.Instances = append(state.Instances, instancesDataSourceInstance{
+			ID:     types.Int64Value(i.Id),
+			Name:   types.StringValue(i.Name),
+			Plan:   types.StringValue(i.Plan),
+			Region: types.StringValue(i.Region),
+			Tags:   tags,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
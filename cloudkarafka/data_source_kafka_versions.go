@@ -0,0 +1,84 @@
+package cloudkarafka
+
+import (
+	"context"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &kafkaVersionsDataSource{}
+	_ datasource.DataSourceWithConfigure = &kafkaVersionsDataSource{}
+)
+
+// NewKafkaVersionsDataSource is a helper function to simplify the provider implementation.
+func NewKafkaVersionsDataSource() datasource.DataSource {
+	return &kafkaVersionsDataSource{}
+}
+
+// kafkaVersionsDataSource is the data source implementation.
+type kafkaVersionsDataSource struct {
+	client *api.API
+}
+
+type kafkaVersionsDataSourceModel struct {
+	Versions []types.String `tfsdk:"versions"`
+	Latest   types.String   `tfsdk:"latest"`
+}
+
+// Metadata returns the data source type name.
+func (d *kafkaVersionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kafka_versions"
+}
+
+// Schema defines the schema for the data source.
+func (d *kafkaVersionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the Apache Kafka versions instances can run.",
+		Attributes: map[string]schema.Attribute{
+			"versions": schema.ListAttribute{
+				Description: "Supported versions, oldest first.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"latest": schema.StringAttribute{
+				Description: "The newest supported version.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *kafkaVersionsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.API)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *kafkaVersionsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	versions, err := d.client.ListKafkaVersions(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list Kafka versions", err.Error())
+		return
+	}
+	state := kafkaVersionsDataSourceModel{
+		Versions: []types.String{},
+		Latest:   types.StringNull(),
+	}
+	for _, v := range versions {
+		state.Versions = append(state.Versions, types.StringValue(v))
+		if state.Latest.IsNull() || api.CompareKafkaVersions(v, state.Latest.ValueString()) > 0 {
+			state.Latest = types.StringValue(v)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,144 @@
+package cloudkarafka
+
+import (
+	"context"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &schemaSubjectDataSource{}
+	_ datasource.DataSourceWithConfigure = &schemaSubjectDataSource{}
+)
+
+// NewSchemaSubjectDataSource is a helper function to simplify the provider implementation.
+func NewSchemaSubjectDataSource() datasource.DataSource {
+	return &schemaSubjectDataSource{}
+}
+
+// schemaSubjectDataSource is the data source implementation.
+type schemaSubjectDataSource struct {
+	client *api.API
+}
+
+type schemaSubjectDataSourceModel struct {
+	InstanceID types.Int64            `tfsdk:"instance_id"`
+	Subject    types.String           `tfsdk:"subject"`
+	Version    types.Int64            `tfsdk:"version"`
+	SchemaID   types.Int64            `tfsdk:"schema_id"`
+	Schema     types.String           `tfsdk:"schema"`
+	SchemaType types.String           `tfsdk:"schema_type"`
+	References []schemaReferenceModel `tfsdk:"references"`
+}
+
+// Metadata returns the data source type name.
+func (d *schemaSubjectDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_registry_subject"
+}
+
+// Schema defines the schema for the data source.
+func (d *schemaSubjectDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Look up a schema registered under a subject of the Schema Registry add-on, by default the latest version.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance with the Schema Registry.",
+				Required:    true,
+			},
+			"subject": schema.StringAttribute{
+				Description: "Name of the subject.",
+				Required:    true,
+			},
+			"version": schema.Int64Attribute{
+				Description: "Version to look up. Defaults to the latest version.",
+				Optional:    true,
+				Computed:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"schema_id": schema.Int64Attribute{
+				Description: "Registry wide id of the schema.",
+				Computed:    true,
+			},
+			"schema": schema.StringAttribute{
+				Description: "The schema definition.",
+				Computed:    true,
+			},
+			"schema_type": schema.StringAttribute{
+				Description: "Format of the schema, AVRO, JSON or PROTOBUF.",
+				Computed:    true,
+			},
+			"references": schema.ListNestedAttribute{
+				Description: "Schemas in other subjects that the schema refers to.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name the schema refers to the other schema by.",
+							Computed:    true,
+						},
+						"subject": schema.StringAttribute{
+							Description: "Subject of the referenced schema.",
+							Computed:    true,
+						},
+						"version": schema.Int64Attribute{
+							Description: "Version of the referenced schema.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *schemaSubjectDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.API)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *schemaSubjectDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state schemaSubjectDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry, err := d.client.SchemaRegistry(ctx, state.InstanceID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read schema", err.Error())
+		return
+	}
+	s, err := registry.ReadSchema(ctx, state.Subject.ValueString(), state.Version.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read schema", err.Error())
+		return
+	}
+	state.Version = types.Int64Value(s.Version)
+	state.SchemaID = types.Int64Value(s.Id)
+	state.Schema = types.StringValue(s.Schema)
+	state.SchemaType = types.StringValue("AVRO")
+	if s.SchemaType != "" {
+		state.SchemaType = types.StringValue(s.SchemaType)
+	}
+	state.References = []schemaReferenceModel{}
+	for _, ref := range s.References {
+		state.References = append(state.References, schemaReferenceModel{
+			Name:    types.StringValue(ref.Name),
+			Subject: types.StringValue(ref.Subject),
+			Version: types.Int64Value(ref.Version),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,101 @@
+package cloudkarafka
+
+import (
+	"context"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &topicDataSource{}
+	_ datasource.DataSourceWithConfigure = &topicDataSource{}
+)
+
+// NewTopicDataSource is a helper function to simplify the provider implementation.
+func NewTopicDataSource() datasource.DataSource {
+	return &topicDataSource{}
+}
+
+// topicDataSource is the data source implementation.
+type topicDataSource struct {
+	client *api.API
+}
+
+type topicDataSourceModel struct {
+	InstanceID        types.Int64             `tfsdk:"instance_id"`
+	Name              types.String            `tfsdk:"name"`
+	Partitions        types.Int64             `tfsdk:"partitions"`
+	ReplicationFactor types.Int64             `tfsdk:"replication_factor"`
+	Config            map[string]types.String `tfsdk:"config"`
+}
+
+// Metadata returns the data source type name.
+func (d *topicDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_topic"
+}
+
+// Schema defines the schema for the data source.
+func (d *topicDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Look up a topic.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance where the topic lives.",
+				Required:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of topic.",
+				Required:    true,
+			},
+			"partitions": schema.Int64Attribute{
+				Description: "Number of partitions for the topic.",
+				Computed:    true,
+			},
+			"replication_factor": schema.Int64Attribute{
+				Description: "Replication factor for the topic.",
+				Computed:    true,
+			},
+			"config": schema.MapAttribute{
+				Description: "Topic configuration as returned by the server.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *topicDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.API)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *topicDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state topicDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	topic, err := d.client.ReadTopic(ctx, state.InstanceID.ValueInt64(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read topic", err.Error())
+		return
+	}
+	state.Partitions = types.Int64Value(topic.Partitions)
+	state.ReplicationFactor = types.Int64Value(topic.Replicas)
+	state.Config = make(map[string]types.String, len(topic.Config))
+	for k, v := range topic.Config {
+		state.Config[k] = types.StringValue(api.ValueString(v))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
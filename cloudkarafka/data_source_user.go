@@ -0,0 +1,85 @@
+package cloudkarafka
+
+import (
+	"context"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &userDataSource{}
+	_ datasource.DataSourceWithConfigure = &userDataSource{}
+)
+
+// NewUserDataSource is a helper function to simplify the provider implementation.
+func NewUserDataSource() datasource.DataSource {
+	return &userDataSource{}
+}
+
+// userDataSource is the data source implementation.
+type userDataSource struct {
+	client *api.API
+}
+
+type userDataSourceModel struct {
+	InstanceID types.Int64  `tfsdk:"instance_id"`
+	Name       types.String `tfsdk:"name"`
+	Type       types.String `tfsdk:"type"`
+}
+
+// Metadata returns the data source type name.
+func (d *userDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+// Schema defines the schema for the data source.
+func (d *userDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Look up a user.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance where the user lives.",
+				Required:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of user.",
+				Required:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "Type of user, either sasl or ssl.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *userDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.API)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *userDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state userDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	user, err := d.client.ReadUser(ctx, state.InstanceID.ValueInt64(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read user", err.Error())
+		return
+	}
+	state.Type = types.StringValue(user.Type)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,98 @@
+package cloudkarafka
+
+import (
+	"context"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &vpcPeeringInfoDataSource{}
+	_ datasource.DataSourceWithConfigure = &vpcPeeringInfoDataSource{}
+)
+
+// NewVPCPeeringInfoDataSource is a helper function to simplify the provider implementation.
+func NewVPCPeeringInfoDataSource() datasource.DataSource {
+	return &vpcPeeringInfoDataSource{}
+}
+
+// vpcPeeringInfoDataSource is the data source implementation.
+type vpcPeeringInfoDataSource struct {
+	client *api.API
+}
+
+type vpcPeeringInfoDataSourceModel struct {
+	VPCID     types.Int64  `tfsdk:"vpc_id"`
+	Region    types.String `tfsdk:"region"`
+	Subnet    types.String `tfsdk:"subnet"`
+	AccountID types.String `tfsdk:"account_id"`
+	NetworkID types.String `tfsdk:"network_id"`
+}
+
+// Metadata returns the data source type name.
+func (d *vpcPeeringInfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vpc_peering_info"
+}
+
+// Schema defines the schema for the data source.
+func (d *vpcPeeringInfoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Get what the peer side of a VPC peering needs to know about a CloudKarafka VPC.",
+		Attributes: map[string]schema.Attribute{
+			"vpc_id": schema.Int64Attribute{
+				Description: "ID of the CloudKarafka VPC.",
+				Required:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region of the VPC.",
+				Computed:    true,
+			},
+			"subnet": schema.StringAttribute{
+				Description: "Subnet of the VPC, to route to from the peer network.",
+				Computed:    true,
+			},
+			"account_id": schema.StringAttribute{
+				Description: "AWS account, GCP project or Azure subscription the VPC belongs to.",
+				Computed:    true,
+			},
+			"network_id": schema.StringAttribute{
+				Description: "AWS VPC ID, GCP network URI or Azure virtual network resource ID of the VPC.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *vpcPeeringInfoDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.API)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *vpcPeeringInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state vpcPeeringInfoDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	info, err := d.client.ReadVPCPeeringInfo(ctx, state.VPCID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read VPC peering info", err.Error())
+		return
+	}
+	state.Region = types.StringValue(info.Region)
+	state.Subnet = types.StringValue(info.Subnet)
+	state.AccountID = types.StringValue(info.AccountId)
+	state.NetworkID = types.StringValue(info.NetworkId)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
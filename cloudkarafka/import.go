@@ -0,0 +1,61 @@
+package cloudkarafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// parseInstanceID parses the instance part of an import ID.
+func parseInstanceID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("instance ID must be a positive number, got %q", s)
+	}
+	return id, nil
+}
+
+// splitImportID splits an import ID of the form <instance_id>/<name>. format
+// describes the expected ID in error messages. Only the first slash
+// separates the two parts.
+func splitImportID(id, format string) (int64, string, error) {
+	instance, name, ok := strings.Cut(id, "/")
+	if !ok || name == "" {
+		return 0, "", fmt.Errorf("expected import ID in the format %s, got %q", format, id)
+	}
+	instanceID, err := parseInstanceID(instance)
+	if err != nil {
+		return 0, "", fmt.Errorf("expected import ID in the format %s: %w", format, err)
+	}
+	return instanceID, name, nil
+}
+
+// importedKey marks a resource in private state between ImportState and the
+// Read that follows it, so that Read can adopt settings that are otherwise
+// only refreshed when already managed.
+const importedKey = "imported"
+
+// privateState is implemented by the private state in resource requests and
+// responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func markImported(ctx context.Context, p privateState) diag.Diagnostics {
+	return p.SetKey(ctx, importedKey, []byte("true"))
+}
+
+// takeImported reports whether the resource was just imported and clears the
+// mark in the response private state.
+func takeImported(ctx context.Context, req, resp privateState) (bool, diag.Diagnostics) {
+	v, diags := req.GetKey(ctx, importedKey)
+	if string(v) != "true" {
+		return false, diags
+	}
+	diags.Append(resp.SetKey(ctx, importedKey, []byte("false"))...)
+	return true, diags
+}
@@ -0,0 +1,73 @@
+package cloudkarafka
+
+import (
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+)
+
+func TestSplitImportID(t *testing.T) {
+	tests := []struct {
+		id       string
+		instance int64
+		name     string
+		wantErr  bool
+	}{
+		{id: "42/orders", instance: 42, name: "orders"},
+		{id: "42/a/b", instance: 42, name: "a/b"},
+		{id: "orders", wantErr: true},
+		{id: "42/", wantErr: true},
+		{id: "/orders", wantErr: true},
+		{id: "abc/orders", wantErr: true},
+		{id: "-1/orders", wantErr: true},
+	}
+	for _, tt := range tests {
+		instance, name, err := splitImportID(tt.id, "<instance_id>/<topic>")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitImportID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			continue
+		}
+		if instance != tt.instance || name != tt.name {
+			t.Errorf("splitImportID(%q) = %d, %q, want %d, %q", tt.id, instance, name, tt.instance, tt.name)
+		}
+	}
+}
+
+func TestParseAclKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    api.AclRule
+		wantErr bool
+	}{
+		{
+			key:  "alice:topic:orders:prefixed:read",
+			want: api.AclRule{User: "alice", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "prefixed", Operation: "read"},
+		},
+		{
+			key: "alice:topic:orders:literal:write:deny:2001:db8::1",
+			want: api.AclRule{User: "alice", Resource: "topic", ResourcePattern: "orders", ResourcePatternType: "literal",
+				Operation: "write", PermissionType: "deny", Host: "2001:db8::1"},
+		},
+		{key: "orders", wantErr: true},
+		{key: "alice:topic:orders:prefixed", wantErr: true},
+		{key: "alice:topic::prefixed:read", wantErr: true},
+		{
+			key: "alice:Topic:orders:PREFIXED:DescribeConfigs",
+			want: api.AclRule{User: "alice", Resource: "Topic", ResourcePattern: "orders", ResourcePatternType: "PREFIXED",
+				Operation: "DescribeConfigs"},
+		},
+		// A colon in the pattern shifts the other parts.
+		{key: "alice:topic:orders:eu:prefixed:read", wantErr: true},
+		{key: "alice:topic:orders:literal:prefixed:read:deny", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAclKey(tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAclKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAclKey(%q) = %+v, want %+v", tt.key, got, tt.want)
+		}
+	}
+}
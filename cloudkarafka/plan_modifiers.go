@@ -0,0 +1,179 @@
+package cloudkarafka
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// partitionDecrease rejects plans that lower the number of partitions, which
+// Kafka does not support. When the boolean attribute at recreate is true the
+// topic is replaced instead.
+func partitionDecrease(recreate path.Path) planmodifier.Int64 {
+	return partitionDecreaseModifier{recreate: recreate}
+}
+
+type partitionDecreaseModifier struct {
+	recreate path.Path
+}
+
+func (m partitionDecreaseModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("Partitions can only be increased, unless %s is true.", m.recreate)
+}
+
+func (m partitionDecreaseModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m partitionDecreaseModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.StateValue.IsNull() || req.PlanValue.IsUnknown() || req.PlanValue.IsNull() {
+		return
+	}
+	if req.PlanValue.ValueInt64() >= req.StateValue.ValueInt64() {
+		return
+	}
+
+	var recreate types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, m.recreate, &recreate)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if recreate.ValueBool() {
+		resp.RequiresReplace = true
+		return
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Cannot decrease partitions",
+		fmt.Sprintf("Kafka cannot lower the number of partitions from %d to %d. Set %s = true to recreate the topic instead, which deletes all its data.",
+			req.StateValue.ValueInt64(), req.PlanValue.ValueInt64(), m.recreate))
+}
+
+// diskSizeIncrease rejects plans that lower the disk size, disks can only
+// grow.
+func diskSizeIncrease() planmodifier.Int64 {
+	return diskSizeIncreaseModifier{}
+}
+
+type diskSizeIncreaseModifier struct{}
+
+func (m diskSizeIncreaseModifier) Description(_ context.Context) string {
+	return "Disk size can only be increased."
+}
+
+func (m diskSizeIncreaseModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m diskSizeIncreaseModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.StateValue.IsNull() || req.PlanValue.IsUnknown() || req.PlanValue.IsNull() {
+		return
+	}
+	if req.PlanValue.ValueInt64() >= req.StateValue.ValueInt64() {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Cannot shrink disk",
+		fmt.Sprintf("The disk size can only be increased, from %d GB to %d GB is a decrease.",
+			req.StateValue.ValueInt64(), req.PlanValue.ValueInt64()))
+}
+
+// kafkaVersionUpgrade rejects plans that move to an older Kafka version,
+// instances can only be upgraded.
+func kafkaVersionUpgrade() planmodifier.String {
+	return kafkaVersionUpgradeModifier{}
+}
+
+type kafkaVersionUpgradeModifier struct{}
+
+func (m kafkaVersionUpgradeModifier) Description(_ context.Context) string {
+	return "Kafka version can only be upgraded."
+}
+
+func (m kafkaVersionUpgradeModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m kafkaVersionUpgradeModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsUnknown() || req.PlanValue.IsNull() {
+		return
+	}
+	if api.CompareKafkaVersions(req.PlanValue.ValueString(), req.StateValue.ValueString()) >= 0 {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Cannot downgrade Kafka",
+		fmt.Sprintf("Instances can only be upgraded, from %s to %s is a downgrade.",
+			req.StateValue.ValueString(), req.PlanValue.ValueString()))
+}
+
+// rotatedBy keeps a computed credential from state, like UseStateForUnknown,
+// unless the map attribute at keepers changes, which rotates it.
+func rotatedBy(keepers path.Path) planmodifier.String {
+	return rotatedByModifier{keepers: keepers}
+}
+
+type rotatedByModifier struct {
+	keepers path.Path
+}
+
+func (m rotatedByModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("The value is kept until %s changes.", m.keepers)
+}
+
+func (m rotatedByModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m rotatedByModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to keep on create, and set values are used as they are.
+	if req.State.Raw.IsNull() || !req.PlanValue.IsUnknown() || !req.ConfigValue.IsNull() {
+		return
+	}
+
+	var planKeepers, stateKeepers types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, m.keepers, &planKeepers)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, m.keepers, &stateKeepers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if planKeepers.Equal(stateKeepers) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// keptUntilChanged keeps a computed list from state, like UseStateForUnknown,
+// unless the string attribute at changed is planned to change, which may
+// change the list too.
+func keptUntilChanged(changed path.Path) planmodifier.List {
+	return keptUntilChangedModifier{changed: changed}
+}
+
+type keptUntilChangedModifier struct {
+	changed path.Path
+}
+
+func (m keptUntilChangedModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("The value is kept until %s changes.", m.changed)
+}
+
+func (m keptUntilChangedModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m keptUntilChangedModifier) PlanModifyList(ctx context.Context, req planmodifier.ListRequest, resp *planmodifier.ListResponse) {
+	// Nothing to keep on create, and known values are used as they are.
+	if req.State.Raw.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	var planned, current types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, m.changed, &planned)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, m.changed, &current)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if planned.Equal(current) {
+		resp.PlanValue = req.StateValue
+	}
+}
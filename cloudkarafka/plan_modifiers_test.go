@@ -0,0 +1,164 @@
+package cloudkarafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// topicPlan returns a topic plan with allow_partition_decrease_by_recreate set.
+func topicPlan(t *testing.T, recreate bool) tfsdk.Plan {
+	ctx := context.Background()
+	var resp resource.SchemaResponse
+	(&topicResource{}).Schema(ctx, resource.SchemaRequest{}, &resp)
+	plan := tfsdk.Plan{
+		Schema: resp.Schema,
+		Raw:    tftypes.NewValue(resp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := plan.SetAttribute(ctx, path.Root("allow_partition_decrease_by_recreate"), recreate)
+	if diags.HasError() {
+		t.Fatalf("failed to build plan: %v", diags)
+	}
+	return plan
+}
+
+func TestPartitionDecrease(t *testing.T) {
+	tests := []struct {
+		name        string
+		state, plan int64
+		recreate    bool
+		wantError   bool
+		wantReplace bool
+	}{
+		{name: "increase", state: 3, plan: 6},
+		{name: "unchanged", state: 3, plan: 3},
+		{name: "decrease", state: 6, plan: 3, wantError: true},
+		{name: "decrease by recreate", state: 6, plan: 3, recreate: true, wantReplace: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.Int64Request{
+				Path:       path.Root("partitions"),
+				Plan:       topicPlan(t, tt.recreate),
+				StateValue: types.Int64Value(tt.state),
+				PlanValue:  types.Int64Value(tt.plan),
+			}
+			var resp planmodifier.Int64Response
+			partitionDecrease(path.Root("allow_partition_decrease_by_recreate")).PlanModifyInt64(context.Background(), req, &resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantError, resp.Diagnostics)
+			}
+			if resp.RequiresReplace != tt.wantReplace {
+				t.Errorf("RequiresReplace = %v, want %v", resp.RequiresReplace, tt.wantReplace)
+			}
+		})
+	}
+}
+
+func TestDiskSizeIncrease(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     types.Int64
+		plan      types.Int64
+		wantError bool
+	}{
+		{name: "increase", state: types.Int64Value(128), plan: types.Int64Value(256)},
+		{name: "unchanged", state: types.Int64Value(256), plan: types.Int64Value(256)},
+		{name: "create", state: types.Int64Null(), plan: types.Int64Value(256)},
+		{name: "removed", state: types.Int64Value(256), plan: types.Int64Null()},
+		{name: "decrease", state: types.Int64Value(256), plan: types.Int64Value(128), wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.Int64Request{
+				Path:       path.Root("disk_size"),
+				StateValue: tt.state,
+				PlanValue:  tt.plan,
+			}
+			var resp planmodifier.Int64Response
+			diskSizeIncrease().PlanModifyInt64(context.Background(), req, &resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestKafkaVersionUpgrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     types.String
+		plan      types.String
+		wantError bool
+	}{
+		{name: "upgrade", state: types.StringValue("3.4.1"), plan: types.StringValue("3.5.1")},
+		{name: "upgrade past 9", state: types.StringValue("3.9.0"), plan: types.StringValue("3.10.0")},
+		{name: "unchanged", state: types.StringValue("3.5.1"), plan: types.StringValue("3.5.1")},
+		{name: "create", state: types.StringNull(), plan: types.StringValue("3.5.1")},
+		{name: "removed", state: types.StringValue("3.5.1"), plan: types.StringNull()},
+		{name: "downgrade", state: types.StringValue("3.5.1"), plan: types.StringValue("2.8.2"), wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.StringRequest{
+				Path:       path.Root("kafka_version"),
+				StateValue: tt.state,
+				PlanValue:  tt.plan,
+			}
+			var resp planmodifier.StringResponse
+			kafkaVersionUpgrade().PlanModifyString(context.Background(), req, &resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestKeptUntilChanged(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	(&instanceResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	withPlan := func(plan string) tftypes.Value {
+		state := tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		}
+		if diags := state.SetAttribute(ctx, path.Root("plan"), plan); diags.HasError() {
+			t.Fatalf("failed to build state: %v", diags)
+		}
+		return state.Raw
+	}
+	brokers, _ := types.ListValueFrom(ctx, types.StringType, []string{"ducky-1-0:9094"})
+
+	tests := []struct {
+		name        string
+		state       string
+		plan        string
+		wantUnknown bool
+	}{
+		{name: "unchanged", state: "ducky", plan: "ducky"},
+		{name: "changed", state: "ducky", plan: "bat", wantUnknown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.ListRequest{
+				Path:       path.Root("brokers"),
+				Plan:       tfsdk.Plan{Schema: schemaResp.Schema, Raw: withPlan(tt.plan)},
+				State:      tfsdk.State{Schema: schemaResp.Schema, Raw: withPlan(tt.state)},
+				StateValue: brokers,
+				PlanValue:  types.ListUnknown(types.StringType),
+			}
+			resp := planmodifier.ListResponse{PlanValue: req.PlanValue}
+			keptUntilChanged(path.Root("plan")).PlanModifyList(ctx, req, &resp)
+			if got := resp.PlanValue.IsUnknown(); got != tt.wantUnknown {
+				t.Errorf("PlanValue = %v, want unknown %v", resp.PlanValue, tt.wantUnknown)
+			}
+		})
+	}
+}
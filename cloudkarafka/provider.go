@@ -4,12 +4,15 @@ import (
 	"context"
 	"os"
 	"terraform-provider-cloudkarafka/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -25,11 +28,17 @@ func New() provider.Provider {
 }
 
 // cloudkarafkaProvider is the provider implementation.
-type cloudkarafkaProvider struct{}
+type cloudkarafkaProvider struct {
+	// apiOptions are applied after the configured ones, tests use them to
+	// speed up polling against a fake server.
+	apiOptions []api.Option
+}
 
 // cloudkarafkaProviderModel maps provider schema data to a Go type.
 type cloudkarafkaProviderModel struct {
-	APIKey types.String `tfsdk:"apikey"`
+	APIKey       types.String `tfsdk:"apikey"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.Int64  `tfsdk:"retry_max_wait"`
 }
 
 // Metadata returns the provider type name.
@@ -47,6 +56,16 @@ func (p *cloudkarafkaProvider) Schema(_ context.Context, _ provider.SchemaReques
 				Required:    true,
 				Sensitive:   true,
 			},
+			"max_retries": schema.Int64Attribute{
+				Description: "How many times to retry a request on rate limiting or transient errors. Defaults to 4.",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(0)},
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				Description: "Maximum number of seconds to wait between two retries. Defaults to 30.",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
 		},
 	}
 }
@@ -92,14 +111,32 @@ func (p *cloudkarafkaProvider) Configure(ctx context.Context, req provider.Confi
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	client := api.New(host, apikey)
+	maxRetries := int64(api.DefaultMaxRetries)
+	if !config.MaxRetries.IsNull() {
+		maxRetries = config.MaxRetries.ValueInt64()
+	}
+	retryMaxWait := api.DefaultRetryMaxWait
+	if !config.RetryMaxWait.IsNull() {
+		retryMaxWait = time.Duration(config.RetryMaxWait.ValueInt64()) * time.Second
+	}
+
+	opts := append([]api.Option{api.WithRetries(int(maxRetries), retryMaxWait)}, p.apiOptions...)
+	client := api.New(host, apikey, opts...)
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *cloudkarafkaProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewInstanceDataSource,
+		NewInstancesDataSource,
+		NewKafkaVersionsDataSource,
+		NewTopicDataSource,
+		NewUserDataSource,
+		NewSchemaSubjectDataSource,
+		NewVPCPeeringInfoDataSource,
+	}
 }
 
 // Resources defines the resources implemented in the provider.
@@ -109,6 +146,14 @@ func (p *cloudkarafkaProvider) Resources(_ context.Context) []func() resource.Re
 		NewTopicResource,
 		NewUserResource,
 		NewAclResource,
+		NewAclSetResource,
+		NewUserRoleResource,
+		NewQuotaResource,
 		NewConfigResource,
+		NewSchemaSubjectResource,
+		NewSchemaCompatibilityResource,
+		NewVPCResource,
+		NewVPCPeeringResource,
+		NewFirewallResource,
 	}
 }
@@ -0,0 +1,77 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"terraform-provider-cloudkarafka/api"
+	"terraform-provider-cloudkarafka/api/apitest"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccProviderConfig is prepended to every acceptance test configuration.
+const testAccProviderConfig = `
+provider "cloudkarafka" {
+  apikey = "test"
+}
+`
+
+// testAccProtoV6ProviderFactories serve a provider that polls the fake API
+// without delay.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"cloudkarafka": providerserver.NewProtocol6WithError(&cloudkarafkaProvider{
+		apiOptions: []api.Option{api.WithPollInterval(time.Millisecond)},
+	}),
+}
+
+// testAccServer starts a fake CloudKarafka API and points the provider at it
+// for the rest of the test.
+func testAccServer(t *testing.T) *apitest.Server {
+	t.Helper()
+	srv := apitest.NewServer()
+	srv.SetReadyAfter(2)
+	t.Cleanup(srv.Close)
+	t.Setenv("CLOUDKARAFKA_HOST", srv.URL)
+	return srv
+}
+
+// checkImportedAttributes verifies that exactly one resource was imported
+// and that it has the wanted attribute values.
+func checkImportedAttributes(states []*terraform.InstanceState, want map[string]string) error {
+	if len(states) != 1 {
+		return fmt.Errorf("expected 1 imported resource, got %d", len(states))
+	}
+	for k, v := range want {
+		if got := states[0].Attributes[k]; got != v {
+			return fmt.Errorf("attribute %s = %q, want %q", k, got, v)
+		}
+	}
+	return nil
+}
+
+// importSteps imports resourceName by id and checks the imported attributes
+// against want, then plans config on the imported state alone, which must
+// show no changes.
+func importSteps(resourceName, id, config string, want map[string]string) []resource.TestStep {
+	return []resource.TestStep{
+		{
+			Config:             config,
+			ResourceName:       resourceName,
+			ImportState:        true,
+			ImportStateId:      id,
+			ImportStatePersist: true,
+			ImportStateCheck: func(states []*terraform.InstanceState) error {
+				return checkImportedAttributes(states, want)
+			},
+		},
+		{
+			Config:   config,
+			PlanOnly: true,
+		},
+	}
+}
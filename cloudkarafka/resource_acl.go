@@ -2,9 +2,14 @@ package cloudkarafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"terraform-provider-cloudkarafka/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -36,13 +41,102 @@ type aclResource struct {
 }
 
 type aclResourceModel struct {
-	InstanceID          types.Int64  `tfsdk:"instance_id"`
-	User                types.String `tfsdk:"username"`
-	ID                  types.Int64  `tfsdk:"id"`
-	Operation           types.String `tfsdk:"operation"`
-	Resource            types.String `tfsdk:"resource"`
-	ResourcePattern     types.String `tfsdk:"resource_pattern"`
-	ResourcePatternType types.String `tfsdk:"resource_pattern_type"`
+	InstanceID          types.Int64    `tfsdk:"instance_id"`
+	User                types.String   `tfsdk:"username"`
+	ID                  types.Int64    `tfsdk:"id"`
+	Operation           types.String   `tfsdk:"operation"`
+	Resource            types.String   `tfsdk:"resource"`
+	ResourcePattern     types.String   `tfsdk:"resource_pattern"`
+	ResourcePatternType types.String   `tfsdk:"resource_pattern_type"`
+	PermissionType      types.String   `tfsdk:"permission_type"`
+	Host                types.String   `tfsdk:"host"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
+}
+
+// aclRule returns the rule as sent to the API.
+func (m *aclResourceModel) aclRule() api.AclRule {
+	return api.AclRule{
+		User:                m.User.ValueString(),
+		Operation:           m.Operation.ValueString(),
+		Resource:            m.Resource.ValueString(),
+		ResourcePattern:     m.ResourcePattern.ValueString(),
+		ResourcePatternType: m.ResourcePatternType.ValueString(),
+		PermissionType:      m.PermissionType.ValueString(),
+		Host:                m.Host.ValueString(),
+	}
+}
+
+// aclImportFormat describes the import IDs of ACL rules.
+const aclImportFormat = "<instance_id>/<acl_id> or " +
+	"<instance_id>/<username>:<resource>:<resource_pattern>:<resource_pattern_type>:<operation>[:<permission_type>[:<host>]]"
+
+// parseAclKey parses the natural key of a rule, as in
+// alice:topic:orders:prefixed:read, optionally followed by the permission
+// type and host. The host comes last as IPv6 addresses contain colons. A
+// resource_pattern with a colon cannot be told apart from the parts after it,
+// such keys are rejected and the rule has to be imported by its id.
+func parseAclKey(s string) (api.AclRule, error) {
+	parts := strings.SplitN(s, ":", 7)
+	if len(parts) < 5 {
+		return api.AclRule{}, fmt.Errorf("expected import ID in the format %s, got %q", aclImportFormat, s)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return api.AclRule{}, fmt.Errorf("expected import ID in the format %s: empty part in %q", aclImportFormat, s)
+		}
+	}
+	if !isAclName(parts[3], "literal", "prefixed") || !isAclName(parts[4], api.AclOperations...) {
+		return api.AclRule{}, fmt.Errorf("cannot import %q by key: a resource_pattern containing ':' is not supported, "+
+			"import the rule by <instance_id>/<acl_id> instead", s)
+	}
+	rule := api.AclRule{
+		User:                parts[0],
+		Resource:            parts[1],
+		ResourcePattern:     parts[2],
+		ResourcePatternType: parts[3],
+		Operation:           parts[4],
+	}
+	if len(parts) > 5 {
+		rule.PermissionType = parts[5]
+	}
+	if len(parts) > 6 {
+		rule.Host = parts[6]
+	}
+	return rule, nil
+}
+
+// isAclName reports whether s is one of names, spelled as aclOneOf accepts.
+func isAclName(s string, names ...string) bool {
+	for _, n := range names {
+		if strings.EqualFold(strings.ReplaceAll(n, "_", ""), strings.ReplaceAll(s, "_", "")) {
+			return true
+		}
+	}
+	return false
+}
+
+// aclOneOf accepts values case insensitively, and without underscores as
+// Kafka writes them, e.g. DescribeConfigs for describe_configs.
+func aclOneOf(values ...string) validator.String {
+	names := append([]string{}, values...)
+	for _, v := range values {
+		if short := strings.ReplaceAll(v, "_", ""); short != v {
+			names = append(names, short)
+		}
+	}
+	return stringvalidator.OneOfCaseInsensitive(names...)
+}
+
+// optionalAclValue returns the value reported by the API for an optional
+// setting. The default is left unset unless it was set before.
+func optionalAclValue(current types.String, value, def string) types.String {
+	if value == "" {
+		value = def
+	}
+	if current.IsNull() && strings.EqualFold(value, def) {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
 }
 
 // Metadata returns the data source type name.
@@ -51,7 +145,7 @@ func (r *aclResource) Metadata(_ context.Context, req resource.MetadataRequest,
 }
 
 // Schema defines the schema for the data source.
-func (r *aclResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *aclResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manage an ACL rule.",
 
@@ -59,10 +153,16 @@ func (r *aclResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 			"instance_id": schema.Int64Attribute{
 				Description: "Id of the instance where we want to manage the rules.",
 				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"username": schema.StringAttribute{
 				Description: "Name of the user to apply the rules on.",
 				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"id": schema.Int64Attribute{
 				Description: "Rule ID.",
@@ -72,22 +172,20 @@ func (r *aclResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 			},
 			"operation": schema.StringAttribute{
-				Description: "Which operation to set the rule on.",
+				Description: "Which operation to set the rule on, one of " + strings.Join(api.AclOperations, ", ") + ".",
 				Required:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
-				Validators: []validator.String{stringvalidator.OneOfCaseInsensitive("read", "write",
-					"create", "delete", "alter", "describe", "clusteraction", "describeconfigs", "alterconfigs",
-					"idempotentwrite", "createtokens", "describetokens", "all")},
+				Validators: []validator.String{aclOneOf(api.AclOperations...)},
 			},
 			"resource": schema.StringAttribute{
-				Description: "Which resource to set the rule on, cluster, topic or group are valid values.",
+				Description: "Which resource to set the rule on, one of " + strings.Join(api.AclResourceTypes, ", ") + ".",
 				Required:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
-				Validators: []validator.String{stringvalidator.OneOfCaseInsensitive("cluster", "topic", "group")},
+				Validators: []validator.String{aclOneOf(api.AclResourceTypes...)},
 			},
 			"resource_pattern": schema.StringAttribute{
 				Description: "Which resource to match.",
@@ -104,6 +202,28 @@ func (r *aclResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 				Validators: []validator.String{stringvalidator.OneOfCaseInsensitive("literal", "prefixed")},
 			},
+			"permission_type": schema.StringAttribute{
+				Description: "Whether the rule allows or denies the operation, allow or deny. Defaults to allow.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{stringvalidator.OneOfCaseInsensitive(api.AclPermissionTypes...)},
+			},
+			"host": schema.StringAttribute{
+				Description: "Address of the clients the rule applies to. Defaults to *, all hosts.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -124,13 +244,16 @@ func (r *aclResource) Create(ctx context.Context, req resource.CreateRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	rule := api.AclRule{
-		Operation:           plan.Operation.ValueString(),
-		Resource:            plan.Resource.ValueString(),
-		ResourcePattern:     plan.ResourcePattern.ValueString(),
-		ResourcePatternType: plan.ResourcePatternType.ValueString(),
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	id, err := r.client.CreateAclRule(plan.InstanceID.ValueInt64(), plan.User.ValueString(), rule)
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	id, err := r.client.CreateAclRule(ctx, plan.InstanceID.ValueInt64(), plan.User.ValueString(), plan.aclRule())
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating rules", err.Error())
 		return
@@ -150,15 +273,45 @@ func (r *aclResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
-	rule, err := r.client.ReadAclRule(state.InstanceID.ValueInt64(), state.ID.ValueInt64())
+	rule, err := r.client.ReadAclRule(ctx, state.InstanceID.ValueInt64(), state.ID.ValueInt64())
+	// Ids are reused once rules are recreated outside Terraform, a rule
+	// under the stored id that allows or denies something else is not this
+	// one. Rules imported by id are not known yet.
+	if err == nil && !state.Operation.IsNull() {
+		if current := state.aclRule(); !current.Same(rule) {
+			err = fmt.Errorf("rule with id %d is another rule now, %w", state.ID.ValueInt64(), api.ErrNotFound)
+		}
+	}
+	if errors.Is(err, api.ErrNotFound) {
+		// Rules recreated outside Terraform get new ids, look for the same
+		// rule under another id.
+		rule, err = r.client.FindAclRule(ctx, state.InstanceID.ValueInt64(), state.aclRule())
+		if err == nil {
+			tflog.Info(ctx, fmt.Sprintf("ACL rule %d not found, using rule %d with the same settings",
+				state.ID.ValueInt64(), rule.Id))
+			state.ID = types.Int64Value(rule.Id)
+		}
+	}
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Error refreshing rules", err.Error())
 		return
 	}
-	state.Operation = types.StringValue(rule.Operation)
-	state.Resource = types.StringValue(rule.Resource)
-	state.ResourcePattern = types.StringValue(rule.ResourcePattern)
-	state.ResourcePatternType = types.StringValue(rule.ResourcePatternType)
+	// Keep the configured spelling while the rule means the same, a change
+	// of spelling alone would replace the rule.
+	if current := state.aclRule(); !current.Same(rule) {
+		state.User = types.StringValue(rule.User)
+		state.Operation = types.StringValue(rule.Operation)
+		state.Resource = types.StringValue(rule.Resource)
+		state.ResourcePattern = types.StringValue(rule.ResourcePattern)
+		state.ResourcePatternType = types.StringValue(rule.ResourcePatternType)
+		state.PermissionType = optionalAclValue(state.PermissionType, rule.PermissionType, "allow")
+		state.Host = optionalAclValue(state.Host, rule.Host, "*")
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -190,7 +343,15 @@ func (r *aclResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
-	err := r.client.DeleteAclRule(state.InstanceID.ValueInt64(), state.ID.ValueInt64())
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteAclRule(ctx, state.InstanceID.ValueInt64(), state.ID.ValueInt64())
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting rule", err.Error())
 	}
@@ -198,6 +359,31 @@ func (r *aclResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 }
 
 func (r *aclResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	instanceID, rule, err := splitImportID(req.ID, aclImportFormat)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	if id, err := strconv.ParseInt(rule, 10, 64); err == nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+		return
+	}
+	// Without an id, Read looks the rule up by its settings.
+	key, err := parseAclKey(rule)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("username"), key.User)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource"), key.Resource)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource_pattern"), key.ResourcePattern)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource_pattern_type"), key.ResourcePatternType)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("operation"), key.Operation)...)
+	if key.PermissionType != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("permission_type"), key.PermissionType)...)
+	}
+	if key.Host != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("host"), key.Host)...)
+	}
 }
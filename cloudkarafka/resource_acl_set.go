@@ -0,0 +1,305 @@
+package cloudkarafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"terraform-provider-cloudkarafka/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &aclSetResource{}
+	_ resource.ResourceWithConfigure   = &aclSetResource{}
+	_ resource.ResourceWithImportState = &aclSetResource{}
+)
+
+// NewAclSetResource is a helper function to simplify the provider implementation.
+func NewAclSetResource() resource.Resource {
+	return &aclSetResource{}
+}
+
+// aclSetResource is the resource implementation.
+type aclSetResource struct {
+	client *api.API
+}
+
+type aclSetResourceModel struct {
+	InstanceID types.Int64       `tfsdk:"instance_id"`
+	User       types.String      `tfsdk:"username"`
+	Rules      []aclSetRuleModel `tfsdk:"rules"`
+	Timeouts   timeouts.Value    `tfsdk:"timeouts"`
+}
+
+type aclSetRuleModel struct {
+	Operation           types.String `tfsdk:"operation"`
+	Resource            types.String `tfsdk:"resource"`
+	ResourcePattern     types.String `tfsdk:"resource_pattern"`
+	ResourcePatternType types.String `tfsdk:"resource_pattern_type"`
+	PermissionType      types.String `tfsdk:"permission_type"`
+	Host                types.String `tfsdk:"host"`
+}
+
+func (m aclSetRuleModel) aclRule() api.AclRule {
+	return api.AclRule{
+		Operation:           m.Operation.ValueString(),
+		Resource:            m.Resource.ValueString(),
+		ResourcePattern:     m.ResourcePattern.ValueString(),
+		ResourcePatternType: m.ResourcePatternType.ValueString(),
+		PermissionType:      m.PermissionType.ValueString(),
+		Host:                m.Host.ValueString(),
+	}
+}
+
+// aclRules returns the rules to send to the API.
+func (m *aclSetResourceModel) aclRules() []api.AclRule {
+	rules := []api.AclRule{}
+	for _, r := range m.Rules {
+		rules = append(rules, r.aclRule())
+	}
+	return rules
+}
+
+// setAclRules copies the rules reported by the API into the model. Rules
+// already in the model are kept as written, so other spellings of the same
+// rule and left out defaults do not show up as changes.
+func (m *aclSetResourceModel) setAclRules(rules []api.AclRule) {
+	current := m.Rules
+	m.Rules = []aclSetRuleModel{}
+	var seen []api.AclRule
+	for _, r := range rules {
+		r.User = ""
+		if containsAclRule(seen, r) {
+			continue
+		}
+		seen = append(seen, r)
+		rule := aclSetRuleModel{
+			Operation:           types.StringValue(r.Operation),
+			Resource:            types.StringValue(r.Resource),
+			ResourcePattern:     types.StringValue(r.ResourcePattern),
+			ResourcePatternType: types.StringValue(r.ResourcePatternType),
+			PermissionType:      optionalAclValue(types.StringNull(), r.PermissionType, "allow"),
+			Host:                optionalAclValue(types.StringNull(), r.Host, "*"),
+		}
+		for _, c := range current {
+			if cr := c.aclRule(); cr.Same(&r) {
+				rule = c
+				break
+			}
+		}
+		m.Rules = append(m.Rules, rule)
+	}
+}
+
+func containsAclRule(rules []api.AclRule, rule api.AclRule) bool {
+	for _, r := range rules {
+		if r.Same(&rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// Metadata returns the data source type name.
+func (r *aclSetResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_acl_set"
+}
+
+// Schema defines the schema for the data source.
+func (r *aclSetResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage all ACL rules of a user. Rules of the user that are not listed are deleted, " +
+			"also the ones added outside Terraform, so do not combine it with `cloudkarafka_aclrule` for the same user. " +
+			"Missing rules are added in a single request.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance where we want to manage the rules.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "Name of the user to apply the rules on.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rules": schema.SetNestedBlock{
+				Description: "The ACL rules of the user.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"operation": schema.StringAttribute{
+							Description: "Which operation to set the rule on, one of " + strings.Join(api.AclOperations, ", ") + ".",
+							Required:    true,
+							Validators:  []validator.String{aclOneOf(api.AclOperations...)},
+						},
+						"resource": schema.StringAttribute{
+							Description: "Which resource to set the rule on, one of " + strings.Join(api.AclResourceTypes, ", ") + ".",
+							Required:    true,
+							Validators:  []validator.String{aclOneOf(api.AclResourceTypes...)},
+						},
+						"resource_pattern": schema.StringAttribute{
+							Description: "Which resource to match.",
+							Required:    true,
+						},
+						"resource_pattern_type": schema.StringAttribute{
+							Description: "How to apply the resource_pattern, literal or prefixed.",
+							Required:    true,
+							Validators:  []validator.String{stringvalidator.OneOfCaseInsensitive("literal", "prefixed")},
+						},
+						"permission_type": schema.StringAttribute{
+							Description: "Whether the rule allows or denies the operation, allow or deny. Defaults to allow.",
+							Optional:    true,
+							Validators:  []validator.String{stringvalidator.OneOfCaseInsensitive(api.AclPermissionTypes...)},
+						},
+						"host": schema.StringAttribute{
+							Description: "Address of the clients the rule applies to. Defaults to *, all hosts.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *aclSetResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.API)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *aclSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan aclSetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	err := r.client.SetAclRules(ctx, plan.InstanceID.ValueInt64(), plan.User.ValueString(), plan.aclRules())
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating rules", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *aclSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state aclSetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := r.client.ListAclRules(ctx, state.InstanceID.ValueInt64(), state.User.ValueString())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error refreshing rules", err.Error())
+		return
+	}
+	state.setAclRules(rules)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update adds and deletes rules so that only the planned ones remain.
+func (r *aclSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan aclSetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	err := r.client.SetAclRules(ctx, plan.InstanceID.ValueInt64(), plan.User.ValueString(), plan.aclRules())
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating rules", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes all rules of the user and removes the Terraform state on success.
+func (r *aclSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state aclSetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.SetAclRules(ctx, state.InstanceID.ValueInt64(), state.User.ValueString(), nil)
+	if errors.Is(err, api.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting rules", err.Error())
+	}
+}
+
+func (r *aclSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	instanceID, user, err := splitImportID(req.ID, "<instance_id>/<username>")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("username"), user)...)
+}
@@ -0,0 +1,232 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAclSetResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.AddAclRule(id, api.AclRule{
+		User:                "alice",
+		Operation:           "read",
+		Resource:            "topic",
+		ResourcePattern:     "orders",
+		ResourcePatternType: "prefixed",
+	})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_acl_set" "test" {
+  instance_id = %d
+  username    = "alice"
+
+  rules {
+    operation             = "read"
+    resource              = "topic"
+    resource_pattern      = "orders"
+    resource_pattern_type = "prefixed"
+  }
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_acl_set.test",
+				ImportState:   true,
+				ImportStateId: "alice",
+				ExpectError:   regexp.MustCompile(`format <instance_id>/<username>`),
+			},
+		}, importSteps("cloudkarafka_acl_set.test", fmt.Sprintf("%d/alice", id), config, map[string]string{
+			"instance_id": fmt.Sprint(id),
+			"username":    "alice",
+			"rules.#":     "1",
+		})...),
+	})
+}
+
+func TestAccAclSetResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.AddAclRule(id, api.AclRule{
+		User:                "bob",
+		Operation:           "all",
+		Resource:            "cluster",
+		ResourcePattern:     "kafka-cluster",
+		ResourcePatternType: "literal",
+	})
+
+	config := func(topics ...string) string {
+		rules := ""
+		for _, topic := range topics {
+			rules += fmt.Sprintf(`
+  rules {
+    operation             = "read"
+    resource              = "topic"
+    resource_pattern      = %q
+    resource_pattern_type = "literal"
+  }
+`, topic)
+		}
+		return testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_acl_set" "test" {
+  instance_id = %d
+  username    = "alice"
+%s}
+`, id, rules)
+	}
+	// checkServer checks the rules of alice on the server, other users must
+	// be left alone.
+	checkServer := func(topics ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			want := make(map[string]bool)
+			for _, topic := range topics {
+				want[topic] = true
+			}
+			var alice, bob int
+			for _, r := range srv.AclRules(id) {
+				switch r.User {
+				case "alice":
+					if !want[r.ResourcePattern] {
+						return fmt.Errorf("unexpected rule on server: %+v", r)
+					}
+					alice++
+				case "bob":
+					bob++
+				}
+			}
+			if alice != len(topics) || bob != 1 {
+				return fmt.Errorf("server has %d rules for alice and %d for bob, want %d and 1", alice, bob, len(topics))
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkServer(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("a", "b", "c"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_acl_set.test", "rules.#", "3"),
+					checkServer("a", "b", "c"),
+				),
+			},
+			{
+				Config: config("a", "c", "d"),
+				Check:  checkServer("a", "c", "d"),
+			},
+			{
+				// A rule added outside Terraform is detected and removed.
+				PreConfig: func() {
+					srv.AddAclRule(id, api.AclRule{
+						User:                "alice",
+						Operation:           "write",
+						Resource:            "topic",
+						ResourcePattern:     "a",
+						ResourcePatternType: "literal",
+					})
+				},
+				Config:             config("a", "c", "d"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config("a", "c", "d"),
+				Check:  checkServer("a", "c", "d"),
+			},
+			{
+				ResourceName:                         "cloudkarafka_acl_set.test",
+				ImportState:                          true,
+				ImportStateId:                        fmt.Sprintf("%d/alice", id),
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "username",
+			},
+		},
+	})
+}
+
+func TestAccAclSetResource_deny(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_acl_set" "test" {
+  instance_id = %d
+  username    = "alice"
+
+  rules {
+    operation             = "all"
+    resource              = "topic"
+    resource_pattern      = "orders"
+    resource_pattern_type = "prefixed"
+  }
+
+  rules {
+    operation             = "Write"
+    resource              = "topic"
+    resource_pattern      = "orders.audit"
+    resource_pattern_type = "literal"
+    permission_type       = "deny"
+  }
+
+  rules {
+    operation             = "IdempotentWrite"
+    resource              = "cluster"
+    resource_pattern      = "kafka-cluster"
+    resource_pattern_type = "literal"
+    host                  = "10.0.0.1"
+  }
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(*terraform.State) error {
+					var deny, host int
+					for _, r := range srv.AclRules(id) {
+						if r.PermissionType == "deny" {
+							deny++
+						}
+						if r.Host == "10.0.0.1" {
+							host++
+						}
+					}
+					if deny != 1 || host != 1 {
+						return fmt.Errorf("server has %d deny rules and %d host rules, want 1 and 1", deny, host)
+					}
+					return nil
+				},
+			},
+			{
+				// The rules as reported by the API must match the configuration.
+				PreConfig: func() {
+					srv.AddAclRule(id, api.AclRule{
+						User:                "alice",
+						Operation:           "idempotent_write",
+						Resource:            "CLUSTER",
+						ResourcePattern:     "kafka-cluster",
+						ResourcePatternType: "literal",
+						PermissionType:      "allow",
+						Host:                "10.0.0.1",
+					})
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,373 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAclResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	aclID := srv.AddAclRule(id, api.AclRule{
+		User:                "alice",
+		Operation:           "read",
+		Resource:            "topic",
+		ResourcePattern:     "orders",
+		ResourcePatternType: "prefixed",
+	})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "alice"
+  operation             = "read"
+  resource              = "topic"
+  resource_pattern      = "orders"
+  resource_pattern_type = "prefixed"
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_aclrule.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%d/orders", id),
+				ExpectError:   regexp.MustCompile(`format\s+<instance_id>/<acl_id>\s+or`),
+			},
+		}, importSteps("cloudkarafka_aclrule.test", fmt.Sprintf("%d/%d", id, aclID), config, map[string]string{
+			"instance_id":           fmt.Sprint(id),
+			"id":                    fmt.Sprint(aclID),
+			"username":              "alice",
+			"operation":             "read",
+			"resource":              "topic",
+			"resource_pattern":      "orders",
+			"resource_pattern_type": "prefixed",
+		})...),
+	})
+}
+
+func TestAccAclResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "alice"
+  operation             = "write"
+  resource              = "topic"
+  resource_pattern      = "orders"
+  resource_pattern_type = "literal"
+}
+`, id),
+				Check: resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "id", "1"),
+			},
+			{
+				ResourceName:      "cloudkarafka_aclrule.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%d/1", id),
+				ImportStateVerify: true,
+			},
+			{
+				// Another user replaces the rule.
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "bob"
+  operation             = "write"
+  resource              = "topic"
+  resource_pattern      = "orders"
+  resource_pattern_type = "literal"
+}
+`, id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "id", "2"),
+					func(*terraform.State) error {
+						if rules := srv.AclRules(id); len(rules) != 1 || rules[0].User != "bob" {
+							return fmt.Errorf("unexpected rules on server: %+v", rules)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAclResource_spelling(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The API spells the names its own way, which is no change.
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "alice"
+  operation             = "DescribeConfigs"
+  resource              = "Topic"
+  resource_pattern      = "orders"
+  resource_pattern_type = "PREFIXED"
+}
+`, id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "operation", "DescribeConfigs"),
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "resource", "Topic"),
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "resource_pattern_type", "PREFIXED"),
+					func(*terraform.State) error {
+						if rules := srv.AclRules(id); len(rules) != 1 || rules[0].Operation != "describe_configs" {
+							return fmt.Errorf("unexpected rules on server: %+v", rules)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAclResource_deny(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	// An allow rule for the same pattern must not be taken for the new rule.
+	srv.AddAclRule(id, api.AclRule{
+		User:                "alice",
+		Operation:           "write",
+		Resource:            "transactional_id",
+		ResourcePattern:     "orders-",
+		ResourcePatternType: "prefixed",
+	})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "alice"
+  operation             = "write"
+  resource              = "transactional_id"
+  resource_pattern      = "orders-"
+  resource_pattern_type = "prefixed"
+  permission_type       = "deny"
+  host                  = "10.0.0.1"
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "alice"
+  operation             = "DescribeConfigs"
+  resource              = "cluster"
+  resource_pattern      = "kafka-cluster"
+  resource_pattern_type = "literal"
+  permission_type       = "bogus"
+}
+`, id),
+				ExpectError: regexp.MustCompile(`permission_type`),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "id", "2"),
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "permission_type", "deny"),
+					func(*terraform.State) error {
+						rules := srv.AclRules(id)
+						if len(rules) != 2 || rules[1].PermissionType != "deny" || rules[1].Host != "10.0.0.1" {
+							return fmt.Errorf("unexpected rules on server: %+v", rules)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "cloudkarafka_aclrule.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%d/2", id),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccAclResource_importByKey(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.AddAclRule(id, api.AclRule{
+		User:                "alice",
+		Operation:           "read",
+		Resource:            "topic",
+		ResourcePattern:     "orders",
+		ResourcePatternType: "prefixed",
+	})
+	aclID := srv.AddAclRule(id, api.AclRule{
+		User:                "alice",
+		Operation:           "read",
+		Resource:            "topic",
+		ResourcePattern:     "orders",
+		ResourcePatternType: "prefixed",
+		PermissionType:      "deny",
+		Host:                "10.0.0.1",
+	})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "alice"
+  operation             = "read"
+  resource              = "topic"
+  resource_pattern      = "orders"
+  resource_pattern_type = "prefixed"
+  permission_type       = "deny"
+  host                  = "10.0.0.1"
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_aclrule.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%d/alice:topic:orders:prefixed:write:deny:10.0.0.1", id),
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		}, importSteps("cloudkarafka_aclrule.test", fmt.Sprintf("%d/alice:topic:orders:prefixed:read:deny:10.0.0.1", id), config, map[string]string{
+			"id":              fmt.Sprint(aclID),
+			"permission_type": "deny",
+			"host":            "10.0.0.1",
+		})...),
+	})
+}
+
+func TestAccAclResource_recreatedOutside(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	rule := api.AclRule{
+		User:                "alice",
+		Operation:           "write",
+		Resource:            "topic",
+		ResourcePattern:     "orders",
+		ResourcePatternType: "literal",
+	}
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_aclrule" "test" {
+  instance_id           = %d
+  username              = "alice"
+  operation             = "write"
+  resource              = "topic"
+  resource_pattern      = "orders"
+  resource_pattern_type = "literal"
+}
+`, id)
+
+	other := api.AclRule{
+		User:                "bob",
+		Operation:           "read",
+		Resource:            "group",
+		ResourcePattern:     "billing",
+		ResourcePatternType: "literal",
+	}
+	// checkRules checks the number of rules on the server, and that alice
+	// has exactly one.
+	checkRules := func(want int) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			var alice int
+			for _, r := range srv.AclRules(id) {
+				if r.User == "alice" {
+					alice++
+				}
+			}
+			if n := len(srv.AclRules(id)); n != want || alice != 1 {
+				return fmt.Errorf("server has %d rules, %d for alice, want %d: %+v", n, alice, want, srv.AclRules(id))
+			}
+			return nil
+		}
+	}
+
+	var newID int64
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			for _, r := range srv.AclRules(id) {
+				if r.User == "alice" {
+					return fmt.Errorf("rule %d of alice still exists", r.Id)
+				}
+			}
+			if n := len(srv.AclRules(id)); n != 2 {
+				return fmt.Errorf("server has %d rules of others, want 2", n)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "id", "1"),
+			},
+			{
+				// The same rule under a new id is adopted, not recreated.
+				PreConfig: func() {
+					srv.DeleteAclRule(id, 1)
+					newID = srv.AddAclRule(id, rule)
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "id", fmt.Sprint(newID))(s)
+					},
+					func(*terraform.State) error {
+						if n := len(srv.AclRules(id)); n != 1 {
+							return fmt.Errorf("server has %d rules, want 1", n)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The old id now holds another rule, the same rule under
+				// a new id is adopted and the other rule is left alone.
+				PreConfig: func() {
+					srv.ReplaceAclRule(id, newID, other)
+					newID = srv.AddAclRule(id, rule)
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "id", fmt.Sprint(newID))(s)
+					},
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "username", "alice"),
+					resource.TestCheckResourceAttr("cloudkarafka_aclrule.test", "operation", "write"),
+					checkRules(2),
+				),
+			},
+			{
+				// Without the same rule anywhere, it is created again.
+				PreConfig: func() {
+					srv.ReplaceAclRule(id, newID, other)
+				},
+				Config: config,
+				Check:  checkRules(3),
+			},
+		},
+	})
+}
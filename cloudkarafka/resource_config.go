@@ -2,9 +2,15 @@ package cloudkarafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"terraform-provider-cloudkarafka/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -12,14 +18,17 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &configResource{}
-	_ resource.ResourceWithConfigure   = &configResource{}
-	_ resource.ResourceWithImportState = &configResource{}
+	_ resource.Resource                   = &configResource{}
+	_ resource.ResourceWithConfigure      = &configResource{}
+	_ resource.ResourceWithImportState    = &configResource{}
+	_ resource.ResourceWithValidateConfig = &configResource{}
 )
 
 // NewConfigrResource is a helper function to simplify the provider implementation.
@@ -33,15 +42,61 @@ type configResource struct {
 }
 
 type configResourceModel struct {
-	InstanceID        types.Int64 `tfsdk:"instance_id"`
-	AutoCreateTopics  types.Bool  `tfsdk:"auto_create_topics_enable"`
-	MinInsyncReplicas types.Int64 `tfsdk:"min_insync_replicas"`
-	LogRetentionBytes types.Int64 `tfsdk:"log_retention_bytes"`
-	LogRetentionMs    types.Int64 `tfsdk:"log_retention_ms"`
-	LogSegmentBytes   types.Int64 `tfsdk:"log_segment_bytes"`
-	NetworkThreads    types.Int64 `tfsdk:"num_network_threads"`
-	IOThreads         types.Int64 `tfsdk:"num_io_threads"`
-	MessageMaxBytes   types.Int64 `tfsdk:"message_max_bytes"`
+	InstanceID        types.Int64             `tfsdk:"instance_id"`
+	AutoCreateTopics  types.Bool              `tfsdk:"auto_create_topics_enable"`
+	MinInsyncReplicas types.Int64             `tfsdk:"min_insync_replicas"`
+	LogRetentionBytes types.Int64             `tfsdk:"log_retention_bytes"`
+	LogRetentionMs    types.Int64             `tfsdk:"log_retention_ms"`
+	LogSegmentBytes   types.Int64             `tfsdk:"log_segment_bytes"`
+	NetworkThreads    types.Int64             `tfsdk:"num_network_threads"`
+	IOThreads         types.Int64             `tfsdk:"num_io_threads"`
+	MessageMaxBytes   types.Int64             `tfsdk:"message_max_bytes"`
+	Properties        map[string]types.String `tfsdk:"properties"`
+	Timeouts          timeouts.Value          `tfsdk:"timeouts"`
+}
+
+// asKafkaConfig maps the configured attributes to broker settings, leaving
+// unset attributes out.
+func (m configResourceModel) asKafkaConfig() *api.KafkaConfig {
+	var properties map[string]string
+	for k, v := range m.Properties {
+		if properties == nil {
+			properties = make(map[string]string, len(m.Properties))
+		}
+		properties[k] = v.ValueString()
+	}
+	return &api.KafkaConfig{
+		AutoCreateTopics:  m.AutoCreateTopics.ValueBoolPointer(),
+		MinInsyncReplicas: m.MinInsyncReplicas.ValueInt64Pointer(),
+		LogRetentionBytes: m.LogRetentionBytes.ValueInt64Pointer(),
+		LogRetentionMs:    m.LogRetentionMs.ValueInt64Pointer(),
+		LogSegmentBytes:   m.LogSegmentBytes.ValueInt64Pointer(),
+		NetworkThreads:    m.NetworkThreads.ValueInt64Pointer(),
+		IOThreads:         m.IOThreads.ValueInt64Pointer(),
+		MessageMaxBytes:   m.MessageMaxBytes.ValueInt64Pointer(),
+		Properties:        properties,
+	}
+}
+
+// adoptNonDefaults sets the attributes whose broker value differs from the
+// Apache Kafka default. Used after import, when no attribute is managed yet.
+func (m *configResourceModel) adoptNonDefaults(config *api.KafkaConfig) {
+	defaults := api.DefaultKafkaConfig()
+	if config.AutoCreateTopics != nil && *config.AutoCreateTopics != *defaults.AutoCreateTopics {
+		m.AutoCreateTopics = types.BoolPointerValue(config.AutoCreateTopics)
+	}
+	adopt := func(attr *types.Int64, value, def *int64) {
+		if value != nil && *value != *def {
+			*attr = types.Int64PointerValue(value)
+		}
+	}
+	adopt(&m.MinInsyncReplicas, config.MinInsyncReplicas, defaults.MinInsyncReplicas)
+	adopt(&m.LogRetentionBytes, config.LogRetentionBytes, defaults.LogRetentionBytes)
+	adopt(&m.LogRetentionMs, config.LogRetentionMs, defaults.LogRetentionMs)
+	adopt(&m.LogSegmentBytes, config.LogSegmentBytes, defaults.LogSegmentBytes)
+	adopt(&m.NetworkThreads, config.NetworkThreads, defaults.NetworkThreads)
+	adopt(&m.IOThreads, config.IOThreads, defaults.IOThreads)
+	adopt(&m.MessageMaxBytes, config.MessageMaxBytes, defaults.MessageMaxBytes)
 }
 
 // Metadata returns the data source type name.
@@ -50,9 +105,9 @@ func (r *configResource) Metadata(_ context.Context, req resource.MetadataReques
 }
 
 // Schema defines the schema for the data source.
-func (r *configResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *configResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manage the Kafka configuration.",
+		Description: "Manage the Kafka configuration. When imported, settings that differ from the Apache Kafka default are adopted, properties are not.",
 		Attributes: map[string]schema.Attribute{
 			"instance_id": schema.Int64Attribute{
 				Description: "Id of the instance where we want to manage the topic.",
@@ -91,10 +146,45 @@ func (r *configResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				Description: "Max size of message.",
 				Optional:    true,
 			},
+			"properties": schema.MapAttribute{
+				Description: "Any other broker properties, keyed by their Kafka name, e.g. `log.cleaner.threads`. " +
+					"Properties removed from this map are reset to the broker default when it is known, " +
+					"others are left at their current value.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`),
+						"must be a Kafka property name",
+					)),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+// ValidateConfig rejects properties that have a dedicated attribute.
+func (r *configResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var properties map[string]types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("properties"), &properties)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, k := range api.KnownKafkaConfigKeys {
+		if _, ok := properties[k]; ok {
+			resp.Diagnostics.AddAttributeError(path.Root("properties").AtMapKey(k), "Conflicting property",
+				fmt.Sprintf("Use the %s attribute instead of setting %s in properties.", strings.ReplaceAll(k, ".", "_"), k))
+		}
+	}
+}
+
 // Configure adds the provider configured client to the data source.
 func (r *configResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -112,30 +202,16 @@ func (r *configResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	cfg := api.NewKafkaConfig()
-	if !plan.AutoCreateTopics.IsNull() {
-		cfg.AutoCreateTopics = plan.AutoCreateTopics.ValueBool()
-	}
-	if !plan.MinInsyncReplicas.IsNull() {
-		cfg.MinInsyncReplicas = plan.MinInsyncReplicas.ValueInt64()
-	}
-	if !plan.LogRetentionBytes.IsNull() {
-		cfg.LogRetentionBytes = plan.LogRetentionBytes.ValueInt64()
-	}
-	if !plan.LogRetentionMs.IsNull() {
-		cfg.LogRetentionMs = plan.LogRetentionBytes.ValueInt64()
-	}
-	if !plan.LogSegmentBytes.IsNull() {
-		cfg.LogSegmentBytes = plan.LogSegmentBytes.ValueInt64()
-	}
-	if !plan.NetworkThreads.IsNull() {
-		cfg.NetworkThreads = plan.NetworkThreads.ValueInt64()
-	}
-	if !plan.IOThreads.IsNull() {
-		cfg.IOThreads = plan.IOThreads.ValueInt64()
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 
-	err := r.client.WriteConfig(plan.InstanceID.ValueInt64(), cfg)
+	cfg := plan.asKafkaConfig()
+	err := r.client.WriteConfig(ctx, plan.InstanceID.ValueInt64(), cfg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error updating kafka config", err.Error())
 		return
@@ -155,32 +231,52 @@ func (r *configResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	config, err := r.client.ReadConfig(state.InstanceID.ValueInt64())
+	config, err := r.client.ReadConfig(ctx, state.InstanceID.ValueInt64())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read kafka config", err.Error())
 		return
 	}
-	state.AutoCreateTopics = types.BoolValue(config.AutoCreateTopics)
-	if config.MinInsyncReplicas != -1 {
-		state.MinInsyncReplicas = types.Int64Value(config.MinInsyncReplicas)
+	imported, diags := takeImported(ctx, req.Private, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	if imported {
+		state.adoptNonDefaults(config)
 	}
-	if config.IOThreads != -1 {
-		state.IOThreads = types.Int64Value(config.IOThreads)
+	// Only refresh settings managed by this resource, the broker reports all of them.
+	if !state.AutoCreateTopics.IsNull() {
+		state.AutoCreateTopics = types.BoolPointerValue(config.AutoCreateTopics)
 	}
-	if config.NetworkThreads != -1 {
-		state.NetworkThreads = types.Int64Value(config.NetworkThreads)
+	if !state.MinInsyncReplicas.IsNull() {
+		state.MinInsyncReplicas = types.Int64PointerValue(config.MinInsyncReplicas)
 	}
-	if config.LogRetentionBytes != -1 {
-		state.LogRetentionBytes = types.Int64Value(config.LogRetentionBytes)
+	if !state.IOThreads.IsNull() {
+		state.IOThreads = types.Int64PointerValue(config.IOThreads)
 	}
-	if config.LogRetentionMs != -1 {
-		state.LogRetentionMs = types.Int64Value(config.LogRetentionMs)
+	if !state.NetworkThreads.IsNull() {
+		state.NetworkThreads = types.Int64PointerValue(config.NetworkThreads)
 	}
-	if config.LogSegmentBytes != -1 {
-		state.LogSegmentBytes = types.Int64Value(config.LogSegmentBytes)
+	if !state.LogRetentionBytes.IsNull() {
+		state.LogRetentionBytes = types.Int64PointerValue(config.LogRetentionBytes)
 	}
-	if config.MessageMaxBytes != -1 {
-		state.MessageMaxBytes = types.Int64Value(config.MessageMaxBytes)
+	if !state.LogRetentionMs.IsNull() {
+		state.LogRetentionMs = types.Int64PointerValue(config.LogRetentionMs)
+	}
+	if !state.LogSegmentBytes.IsNull() {
+		state.LogSegmentBytes = types.Int64PointerValue(config.LogSegmentBytes)
+	}
+	if !state.MessageMaxBytes.IsNull() {
+		state.MessageMaxBytes = types.Int64PointerValue(config.MessageMaxBytes)
+	}
+	for k := range state.Properties {
+		if v, ok := config.Properties[k]; ok {
+			state.Properties[k] = types.StringValue(v)
+		} else {
+			delete(state.Properties, k)
+		}
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -191,13 +287,88 @@ func (r *configResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *configResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state configResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	for k := range state.Properties {
+		if _, ok := plan.Properties[k]; !ok {
+			warnPropertyNotReset(k, &resp.Diagnostics)
+		}
+	}
+
+	// Settings removed from the configuration go back to the broker default.
+	cfg := state.asKafkaConfig().ResetToDefaults()
+	cfg.Merge(plan.asKafkaConfig())
+	if !cfg.IsEmpty() {
+		err := r.client.WriteConfig(ctx, plan.InstanceID.ValueInt64(), cfg)
+		if err != nil {
+			resp.Diagnostics.AddError("Error updating kafka config", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *configResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state configResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	for k := range state.Properties {
+		warnPropertyNotReset(k, &resp.Diagnostics)
+	}
+
+	cfg := state.asKafkaConfig().ResetToDefaults()
+	if cfg.IsEmpty() {
+		return
+	}
+	err := r.client.WriteConfig(ctx, state.InstanceID.ValueInt64(), cfg)
+	if err != nil {
+		resp.Diagnostics.AddError("Error resetting kafka config", err.Error())
+		return
+	}
+}
+
+// warnPropertyNotReset warns when the property name is no longer managed but
+// has no known default to reset it to.
+func warnPropertyNotReset(name string, diags *diag.Diagnostics) {
+	if _, ok := api.PropertyDefault(name); ok {
+		return
+	}
+	diags.AddWarning("Property not reset",
+		fmt.Sprintf("%s has no known default and is left at its current value on the broker.", name))
 }
 
 func (r *configResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	instanceID, err := parseInstanceID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("expected import ID in the format <instance_id>: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	resp.Diagnostics.Append(markImported(ctx, resp.Private)...)
 }
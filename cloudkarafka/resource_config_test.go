@@ -0,0 +1,181 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestConfigResourceModelAsKafkaConfig(t *testing.T) {
+	model := configResourceModel{
+		AutoCreateTopics:  types.BoolValue(false),
+		MinInsyncReplicas: types.Int64Null(),
+		LogRetentionBytes: types.Int64Value(1024),
+		LogRetentionMs:    types.Int64Value(86400000),
+		LogSegmentBytes:   types.Int64Null(),
+		NetworkThreads:    types.Int64Null(),
+		IOThreads:         types.Int64Value(10),
+		MessageMaxBytes:   types.Int64Value(2097152),
+		Properties: map[string]types.String{
+			"log.cleaner.threads": types.StringValue("2"),
+		},
+	}
+	want := "auto.create.topics.enable=false\n" +
+		"log.retention.bytes=1024\n" +
+		"log.retention.ms=86400000\n" +
+		"num.io.threads=10\n" +
+		"message.max.bytes=2097152\n" +
+		"log.cleaner.threads=2\n"
+	if got := model.asKafkaConfig().AsProperties(); got != want {
+		t.Errorf("asKafkaConfig().AsProperties() = %q, want %q", got, want)
+	}
+}
+
+func TestAccConfigResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.SetConfig(id, map[string]string{
+		"auto.create.topics.enable": "false",
+		"log.retention.ms":          "604800000",
+		"min.insync.replicas":       "2",
+		"num.io.threads":            "8",
+	})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_kafkaconfig" "test" {
+  instance_id               = %d
+  auto_create_topics_enable = false
+  min_insync_replicas       = 2
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_kafkaconfig.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%d/kafka", id),
+				ExpectError:   regexp.MustCompile(`format <instance_id>`),
+			},
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_kafkaconfig.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprint(id + 1),
+				ExpectError:   regexp.MustCompile(`Cannot import non-existent remote object`),
+			},
+		}, importSteps("cloudkarafka_kafkaconfig.test", fmt.Sprint(id), config, map[string]string{
+			"instance_id":               fmt.Sprint(id),
+			"auto_create_topics_enable": "false",
+			"min_insync_replicas":       "2",
+			"log_retention_ms":          "",
+			"num_io_threads":            "",
+		})...),
+	})
+}
+
+func TestAccConfigResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			config := srv.Config(id)
+			if config["min.insync.replicas"] != "1" || config["log.cleaner.threads"] != "1" {
+				return fmt.Errorf("unexpected config after destroy: %v", config)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_kafkaconfig" "test" {
+  instance_id         = %d
+  min_insync_replicas = 2
+}
+`, id),
+				Check: resource.TestCheckResourceAttr("cloudkarafka_kafkaconfig.test", "min_insync_replicas", "2"),
+			},
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_kafkaconfig" "test" {
+  instance_id         = %d
+  min_insync_replicas = 3
+  properties = {
+    "log.cleaner.threads" = "2"
+  }
+}
+`, id),
+				Check: func(*terraform.State) error {
+					config := srv.Config(id)
+					if config["min.insync.replicas"] != "3" || config["log.cleaner.threads"] != "2" {
+						return fmt.Errorf("unexpected config on server: %v", config)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_kafkaconfig" "test" {
+  instance_id         = %d
+  min_insync_replicas = 3
+}
+`, id),
+				Check: func(*terraform.State) error {
+					config := srv.Config(id)
+					if config["min.insync.replicas"] != "3" || config["log.cleaner.threads"] != "1" {
+						return fmt.Errorf("removed property not reset on server: %v", config)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccConfigResource_unknownPropertyDefault(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	post := fmt.Sprintf("POST /api/instances/%d/config/kafka", id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if config := srv.Config(id); config["custom.setting"] != "x" {
+				return fmt.Errorf("unexpected config after destroy: %v", config)
+			}
+			posts := 0
+			for _, r := range srv.Requests() {
+				if r == post {
+					posts++
+				}
+			}
+			if posts != 1 {
+				return fmt.Errorf("config written %d times, want once: %v", posts, srv.Requests())
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_kafkaconfig" "test" {
+  instance_id = %d
+  properties = {
+    "custom.setting" = "x"
+  }
+}
+`, id),
+				Check: resource.TestCheckResourceAttr("cloudkarafka_kafkaconfig.test", "properties.custom.setting", "x"),
+			},
+		},
+	})
+}
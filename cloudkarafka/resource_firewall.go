@@ -0,0 +1,282 @@
+package cloudkarafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"terraform-provider-cloudkarafka/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &firewallResource{}
+	_ resource.ResourceWithConfigure   = &firewallResource{}
+	_ resource.ResourceWithImportState = &firewallResource{}
+)
+
+// NewFirewallResource is a helper function to simplify the provider implementation.
+func NewFirewallResource() resource.Resource {
+	return &firewallResource{}
+}
+
+// firewallResource is the resource implementation.
+type firewallResource struct {
+	client *api.API
+}
+
+type firewallResourceModel struct {
+	InstanceID types.Int64         `tfsdk:"instance_id"`
+	Rules      []firewallRuleModel `tfsdk:"rules"`
+	Timeouts   timeouts.Value      `tfsdk:"timeouts"`
+}
+
+type firewallRuleModel struct {
+	IP          types.String   `tfsdk:"ip"`
+	Services    []types.String `tfsdk:"services"`
+	Ports       []types.Int64  `tfsdk:"ports"`
+	Description types.String   `tfsdk:"description"`
+}
+
+// firewallRules returns the rules to send to the API.
+func (m *firewallResourceModel) firewallRules() []api.FirewallRule {
+	rules := []api.FirewallRule{}
+	for _, r := range m.Rules {
+		rule := api.FirewallRule{
+			IP:          r.IP.ValueString(),
+			Services:    []string{},
+			Ports:       []int64{},
+			Description: r.Description.ValueString(),
+		}
+		for _, s := range r.Services {
+			rule.Services = append(rule.Services, s.ValueString())
+		}
+		for _, p := range r.Ports {
+			rule.Ports = append(rule.Ports, p.ValueInt64())
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// setFirewallRules copies the rules reported by the API into the model.
+// Empty services, ports and descriptions are left unset.
+func (m *firewallResourceModel) setFirewallRules(rules []api.FirewallRule) {
+	m.Rules = []firewallRuleModel{}
+	for _, r := range rules {
+		rule := firewallRuleModel{
+			IP:          types.StringValue(r.IP),
+			Description: types.StringNull(),
+		}
+		if r.Description != "" {
+			rule.Description = types.StringValue(r.Description)
+		}
+		for _, s := range r.Services {
+			rule.Services = append(rule.Services, types.StringValue(s))
+		}
+		for _, p := range r.Ports {
+			rule.Ports = append(rule.Ports, types.Int64Value(p))
+		}
+		m.Rules = append(m.Rules, rule)
+	}
+}
+
+// Metadata returns the data source type name.
+func (r *firewallResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_security_firewall"
+}
+
+// Schema defines the schema for the data source.
+func (r *firewallResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage the firewall of an instance. The rules replace all rules on the instance, " +
+			"rules added outside Terraform show up as changes. Destroying the resource puts back the default rule, " +
+			"which opens all services to any address.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance where we want to manage the firewall.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rules": schema.SetNestedBlock{
+				Description: "Addresses allowed to connect, and to what.",
+				Validators:  []validator.Set{setvalidator.SizeAtLeast(1)},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"ip": schema.StringAttribute{
+							Description: "Addresses to allow, as a CIDR block, e.g. 203.0.113.7/32.",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(
+									regexp.MustCompile(`^[0-9a-fA-F.:]+/\d+$`),
+									"must be a CIDR block",
+								),
+							},
+						},
+						"services": schema.SetAttribute{
+							Description: "Services to open, any of " + strings.Join(api.FirewallServices, ", ") + ". Leave out to open none.",
+							ElementType: types.StringType,
+							Optional:    true,
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+								setvalidator.ValueStringsAre(stringvalidator.OneOf(api.FirewallServices...)),
+							},
+						},
+						"ports": schema.SetAttribute{
+							Description: "Custom ports to open. Leave out to open none.",
+							ElementType: types.Int64Type,
+							Optional:    true,
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+								setvalidator.ValueInt64sAre(int64validator.Between(1, 65535)),
+							},
+						},
+						"description": schema.StringAttribute{
+							Description: "What the rule is for.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *firewallResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.API)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *firewallResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	err := r.client.UpdateFirewall(ctx, plan.InstanceID.ValueInt64(), plan.firewallRules())
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating firewall", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *firewallResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state firewallResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := r.client.ReadFirewall(ctx, state.InstanceID.ValueInt64())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read firewall", err.Error())
+		return
+	}
+	state.setFirewallRules(rules)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *firewallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	err := r.client.UpdateFirewall(ctx, plan.InstanceID.ValueInt64(), plan.firewallRules())
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating firewall", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *firewallResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state firewallResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.ResetFirewall(ctx, state.InstanceID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Error resetting firewall", err.Error())
+		return
+	}
+}
+
+func (r *firewallResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	instanceID, err := parseInstanceID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("expected import ID in the format <instance_id>: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+}
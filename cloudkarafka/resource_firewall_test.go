@@ -0,0 +1,181 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccFirewallResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.SetFirewall(id, []api.FirewallRule{
+		{IP: "203.0.113.7/32", Services: []string{"KAFKA"}, Description: "NAT gateway"},
+		{IP: "10.0.0.0/16", Ports: []int64{8443}},
+	})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_security_firewall" "test" {
+  instance_id = %d
+
+  rules {
+    ip          = "203.0.113.7/32"
+    services    = ["KAFKA"]
+    description = "NAT gateway"
+  }
+
+  rules {
+    ip    = "10.0.0.0/16"
+    ports = [8443]
+  }
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_security_firewall.test",
+				ImportState:   true,
+				ImportStateId: "test",
+				ExpectError:   regexp.MustCompile(`format <instance_id>`),
+			},
+		}, importSteps("cloudkarafka_security_firewall.test", fmt.Sprint(id), config, map[string]string{
+			"instance_id": fmt.Sprint(id),
+			"rules.#":     "2",
+		})...),
+	})
+}
+
+func TestAccFirewallResource_emptySet(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// An empty set reads back as left out, which would show a
+				// change on every plan.
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_security_firewall" "test" {
+  instance_id = %d
+
+  rules {
+    ip       = "203.0.113.7/32"
+    services = []
+    ports    = [8443]
+  }
+}
+`, id),
+				ExpectError: regexp.MustCompile(`must contain at least 1 elements`),
+			},
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_security_firewall" "test" {
+  instance_id = %d
+
+  rules {
+    ip       = "203.0.113.7/32"
+    services = ["KAFKA"]
+    ports    = []
+  }
+}
+`, id),
+				ExpectError: regexp.MustCompile(`must contain at least 1 elements`),
+			},
+		},
+	})
+}
+
+func TestAccFirewallResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_security_firewall" "test" {
+  instance_id = %d
+
+  rules {
+    ip          = "203.0.113.7/32"
+    services    = ["KAFKA", "SCHEMA_REGISTRY"]
+    description = "NAT gateway"
+  }
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			rules := srv.Firewall(id)
+			if len(rules) != 1 || rules[0].IP != "0.0.0.0/0" {
+				return fmt.Errorf("firewall was not reset: %+v", rules)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_security_firewall.test", "rules.#", "1"),
+					func(*terraform.State) error {
+						rules := srv.Firewall(id)
+						if len(rules) != 1 || rules[0].IP != "203.0.113.7/32" || len(rules[0].Services) != 2 {
+							return fmt.Errorf("unexpected firewall on server: %+v", rules)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// A rule added outside Terraform is detected and removed.
+				PreConfig: func() {
+					rules := srv.Firewall(id)
+					srv.SetFirewall(id, append(rules, api.FirewallRule{IP: "0.0.0.0/0", Services: []string{"KAFKA"}}))
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: func(*terraform.State) error {
+					if rules := srv.Firewall(id); len(rules) != 1 {
+						return fmt.Errorf("manual rule was not removed: %+v", rules)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_security_firewall" "test" {
+  instance_id = %d
+
+  rules {
+    ip       = "203.0.113.7/32"
+    services = ["KAFKA", "SCHEMA_REGISTRY"]
+    ports    = [8443]
+  }
+
+  rules {
+    ip       = "198.51.100.0/24"
+    services = ["KAFKA_SSL"]
+  }
+}
+`, id),
+				Check: func(*terraform.State) error {
+					if rules := srv.Firewall(id); len(rules) != 2 {
+						return fmt.Errorf("unexpected firewall on server: %+v", rules)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
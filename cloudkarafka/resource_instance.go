@@ -2,10 +2,15 @@ package cloudkarafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"terraform-provider-cloudkarafka/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -47,6 +52,56 @@ type instanceResourceModel struct {
 	KafkaVersion types.String   `tfsdk:"kafka_version"`
 	VPCSubnet    types.String   `tfsdk:"vpc_subnet"`
 	VPCId        types.Int64    `tfsdk:"vpc_id"`
+	Brokers      types.List     `tfsdk:"brokers"`
+	Username     types.String   `tfsdk:"username"`
+	Password     types.String   `tfsdk:"password"`
+	ApiKey       types.String   `tfsdk:"apikey"`
+	// Only used by Terraform, not stored in the API.
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	KeepVPC            types.Bool     `tfsdk:"keep_vpc"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// updateRequest returns the settings that can be changed in place.
+func (m *instanceResourceModel) updateRequest() api.UpdateInstanceRequest {
+	var tags []string
+	for _, t := range m.Tags {
+		tags = append(tags, t.ValueString())
+	}
+	return api.UpdateInstanceRequest{
+		Name:         m.Name.ValueString(),
+		Plan:         m.Plan.ValueString(),
+		DiskSize:     m.DiskSize.ValueInt64(),
+		Tags:         tags,
+		KafkaVersion: m.KafkaVersion.ValueString(),
+	}
+}
+
+// setUpdateRequest copies settings applied by an update into the model.
+func (m *instanceResourceModel) setUpdateRequest(r api.UpdateInstanceRequest) {
+	m.Name = types.StringValue(r.Name)
+	m.Plan = types.StringValue(r.Plan)
+	if !m.DiskSize.IsNull() || r.DiskSize != 0 {
+		m.DiskSize = types.Int64Value(r.DiskSize)
+	}
+	if !m.KafkaVersion.IsNull() || r.KafkaVersion != "" {
+		m.KafkaVersion = types.StringValue(r.KafkaVersion)
+	}
+	var tags []types.String
+	for _, t := range r.Tags {
+		tags = append(tags, types.StringValue(t))
+	}
+	m.Tags = tags
+}
+
+// setConnectionInfo copies the broker list and credentials from the API response.
+func (m *instanceResourceModel) setConnectionInfo(ctx context.Context, instance api.InstanceResponse) diag.Diagnostics {
+	brokers, diags := types.ListValueFrom(ctx, types.StringType, instance.Brokers())
+	m.Brokers = brokers
+	m.Username = types.StringValue(instance.Username)
+	m.Password = types.StringValue(instance.Password)
+	m.ApiKey = types.StringValue(instance.ApiKey)
+	return diags
 }
 
 // Metadata returns the data source type name.
@@ -55,7 +110,7 @@ func (r *instanceResource) Metadata(_ context.Context, req resource.MetadataRequ
 }
 
 // Schema defines the schema for the data source.
-func (r *instanceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *instanceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manage an instance.",
 		Attributes: map[string]schema.Attribute{
@@ -75,9 +130,12 @@ func (r *instanceResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Required:    true,
 			},
 			"disk_size": schema.Int64Attribute{
-				Description: "Disk size for each broker.",
+				Description: "Disk size for each broker, in GB. Can only be increased.",
 				Optional:    true,
 				Validators:  []validator.Int64{int64validator.AtLeast(128)},
+				PlanModifiers: []planmodifier.Int64{
+					diskSizeIncrease(),
+				},
 			},
 			"tags": schema.SetAttribute{
 				Description: "Instance tags.",
@@ -95,7 +153,7 @@ func (r *instanceResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				},
 			},
 			"kafka_version": schema.StringAttribute{
-				Description: "Which Apache Kafka version to use.",
+				Description: "Which Apache Kafka version to use. Changing it upgrades the instance in place with a rolling restart of the brokers, downgrades are not supported. See the `cloudkarafka_kafka_versions` data source for the available versions.",
 				Optional:    true,
 				Validators: []validator.String{
 					stringvalidator.RegexMatches(
@@ -103,12 +161,16 @@ func (r *instanceResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 						"Version must be of format X.X.X",
 					),
 				},
+				PlanModifiers: []planmodifier.String{
+					kafkaVersionUpgrade(),
+				},
 			},
 			"vpc_subnet": schema.StringAttribute{
 				Description: "Subnet for the VPC.",
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -117,9 +179,56 @@ func (r *instanceResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
 					int64planmodifier.RequiresReplace(),
 				},
 			},
+			"brokers": schema.ListAttribute{
+				Description: "Broker connection strings, host:port.",
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					keptUntilChanged(path.Root("plan")),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "Username for the default SASL user.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"password": schema.StringAttribute{
+				Description: "Password for the default SASL user.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"apikey": schema.StringAttribute{
+				Description: "API key for the instance.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Refuse to destroy the instance while true. Set it to false and apply before destroying.",
+				Optional:    true,
+			},
+			"keep_vpc": schema.BoolAttribute{
+				Description: "Keep the VPC when the instance is destroyed, so other instances can use it.",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -142,6 +251,14 @@ func (r *instanceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, 30*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	var tags []string
 	for _, t := range plan.Tags {
 		tags = append(tags, t.ValueString())
@@ -160,7 +277,7 @@ func (r *instanceResource) Create(ctx context.Context, req resource.CreateReques
 		createRequest.VpcSubnet = plan.VPCSubnet.ValueString()
 	}
 
-	instance, err := r.client.CreateInstance(createRequest)
+	instance, err := r.client.CreateInstance(ctx, createRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating instance", err.Error())
 		return
@@ -169,6 +286,7 @@ func (r *instanceResource) Create(ctx context.Context, req resource.CreateReques
 	plan.ID = types.Int64Value(instance.Id)
 	plan.VPCId = types.Int64Value(instance.Vpc.Id)
 	plan.VPCSubnet = types.StringValue(instance.Vpc.Subnet)
+	resp.Diagnostics.Append(plan.setConnectionInfo(ctx, instance)...)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
@@ -185,11 +303,18 @@ func (r *instanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	instance, err := r.client.ReadInstance(state.ID.ValueInt64())
+	instance, err := r.client.ReadInstance(ctx, state.ID.ValueInt64())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read instance state", err.Error())
 		return
 	}
+	imported, diags := takeImported(ctx, req.Private, resp.Private)
+	resp.Diagnostics.Append(diags...)
 	var tags []types.String
 	for _, t := range instance.Tags {
 		tags = append(tags, types.StringValue(t))
@@ -197,8 +322,17 @@ func (r *instanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 	state.Name = types.StringValue(instance.Name)
 	state.Tags = tags
 	state.Plan = types.StringValue(instance.Plan)
+	state.Region = types.StringValue(instance.Region)
 	state.VPCSubnet = types.StringValue(instance.Vpc.Subnet)
 	state.VPCId = types.Int64Value(int64(instance.Vpc.Id))
+	// Optional settings are only refreshed when managed, or adopted on import.
+	if instance.KafkaVersion != "" && (imported || !state.KafkaVersion.IsNull()) {
+		state.KafkaVersion = types.StringValue(instance.KafkaVersion)
+	}
+	if instance.DiskSize > 0 && (imported || !state.DiskSize.IsNull()) {
+		state.DiskSize = types.Int64Value(instance.DiskSize)
+	}
+	resp.Diagnostics.Append(state.setConnectionInfo(ctx, instance)...)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -208,30 +342,44 @@ func (r *instanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *instanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var (
-		plan *instanceResourceModel
-	)
+	var plan, state instanceResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var tags []string
-	for _, t := range plan.Tags {
-		tags = append(tags, t.ValueString())
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 30*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	err := r.client.UpdateInstance(plan.ID.ValueInt64(), api.UpdateInstanceRequest{
-		Name:     plan.Name.ValueString(),
-		Plan:     plan.Plan.ValueString(),
-		DiskSize: plan.DiskSize.ValueInt64(),
-		Tags:     tags,
-	})
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	current, wanted := state.updateRequest(), plan.updateRequest()
+	if plan.DiskSize.IsNull() {
+		// Leaving disk_size out keeps the current disk.
+		wanted.DiskSize = current.DiskSize
+	}
+	applied, err := r.client.UpdateInstance(ctx, state.ID.ValueInt64(), current, wanted)
 	if err != nil {
 		resp.Diagnostics.AddError("Error updating instance", err.Error())
+		// Keep the phases that went through, the next apply retries the rest.
+		state.setUpdateRequest(applied)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	// A new plan can move the brokers, refresh the connection info.
+	instance, err := r.client.ReadInstance(ctx, state.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read instance state", err.Error())
 		return
 	}
+	resp.Diagnostics.Append(plan.setConnectionInfo(ctx, instance)...)
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -246,7 +394,22 @@ func (r *instanceResource) Delete(ctx context.Context, req resource.DeleteReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.client.DeleteInstance(state.ID.ValueInt64(), false)
+
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Instance is protected",
+			fmt.Sprintf("Instance %d has deletion_protection set. Set it to false and apply before destroying the instance.", state.ID.ValueInt64()))
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteInstance(ctx, state.ID.ValueInt64(), state.KeepVPC.ValueBool())
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting instance", err.Error())
 		return
@@ -257,5 +420,11 @@ func (r *instanceResource) Delete(ctx context.Context, req resource.DeleteReques
 
 func (r *instanceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("Expected a numeric instance ID, got %q", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(markImported(ctx, resp.Private)...)
 }
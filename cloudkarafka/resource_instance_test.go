@@ -0,0 +1,257 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccInstanceResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{
+		Name:         "test",
+		Plan:         "ducky",
+		Region:       "amazon-web-services::us-east-1",
+		Tags:         []string{"production"},
+		KafkaVersion: "3.5.1",
+		DiskSize:     256,
+		BrokerUrl:    "broker-1:9094,broker-2:9094",
+		Username:     "admin",
+		Password:     "secret",
+		ApiKey:       "key",
+	})
+
+	config := testAccProviderConfig + `
+resource "cloudkarafka_instance" "test" {
+  name          = "test"
+  plan          = "ducky"
+  region        = "amazon-web-services::us-east-1"
+  tags          = ["production"]
+  kafka_version = "3.5.1"
+  disk_size     = 256
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_instance.test",
+				ImportState:   true,
+				ImportStateId: "test",
+				ExpectError:   regexp.MustCompile(`Expected a numeric instance ID`),
+			},
+		}, importSteps("cloudkarafka_instance.test", fmt.Sprint(id), config, map[string]string{
+			"id":            fmt.Sprint(id),
+			"name":          "test",
+			"plan":          "ducky",
+			"region":        "amazon-web-services::us-east-1",
+			"tags.#":        "1",
+			"tags.0":        "production",
+			"kafka_version": "3.5.1",
+			"disk_size":     "256",
+			"brokers.#":     "2",
+			"username":      "admin",
+		})...),
+	})
+}
+
+func TestAccInstanceResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, r := range s.RootModule().Resources {
+				var id int64
+				fmt.Sscan(r.Primary.ID, &id)
+				if _, ok := srv.Instance(id); ok {
+					return fmt.Errorf("instance %d still exists", id)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_instance" "test" {
+  name   = "test"
+  plan   = "ducky"
+  region = "amazon-web-services::us-east-1"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "id", "1"),
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "brokers.#", "2"),
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "username", "user-1"),
+					resource.TestCheckNoResourceAttr("cloudkarafka_instance.test", "kafka_version"),
+				),
+			},
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_instance" "test" {
+  name   = "renamed"
+  plan   = "ducky"
+  region = "amazon-web-services::us-east-1"
+  tags   = ["production"]
+}
+`,
+				Check: func(*terraform.State) error {
+					instance, _ := srv.Instance(1)
+					if instance.Name != "renamed" || len(instance.Tags) != 1 {
+						return fmt.Errorf("unexpected instance on server: %+v", instance)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_instance" "test" {
+  name      = "renamed"
+  plan      = "bat"
+  region    = "amazon-web-services::us-east-1"
+  tags      = ["production"]
+  disk_size = 256
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The new plan moved the brokers.
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "brokers.0", "bat-1-0:9094"),
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "username", "user-1"),
+					func(*terraform.State) error {
+						instance, _ := srv.Instance(1)
+						if instance.Plan != "bat" || instance.DiskSize != 256 {
+							return fmt.Errorf("unexpected instance on server: %+v", instance)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_instance" "test" {
+  name      = "renamed"
+  plan      = "bat"
+  region    = "amazon-web-services::us-east-1"
+  tags      = ["production"]
+  disk_size = 128
+}
+`,
+				ExpectError: regexp.MustCompile(`Cannot shrink disk`),
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_upgrade(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_instance" "test" {
+  name          = "test"
+  plan          = "ducky"
+  region        = "amazon-web-services::us-east-1"
+  kafka_version = "3.4.1"
+}
+`,
+				Check: resource.TestCheckResourceAttr("cloudkarafka_instance.test", "kafka_version", "3.4.1"),
+			},
+			{
+				Config: testAccProviderConfig + `
+data "cloudkarafka_kafka_versions" "available" {}
+
+resource "cloudkarafka_instance" "test" {
+  name          = "test"
+  plan          = "ducky"
+  region        = "amazon-web-services::us-east-1"
+  kafka_version = data.cloudkarafka_kafka_versions.available.latest
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cloudkarafka_kafka_versions.available", "versions.#", "4"),
+					resource.TestCheckResourceAttr("data.cloudkarafka_kafka_versions.available", "latest", "3.6.1"),
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "kafka_version", "3.6.1"),
+					func(*terraform.State) error {
+						instance, _ := srv.Instance(1)
+						if instance.KafkaVersion != "3.6.1" {
+							return fmt.Errorf("instance runs Kafka %s, want 3.6.1", instance.KafkaVersion)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_instance" "test" {
+  name          = "test"
+  plan          = "ducky"
+  region        = "amazon-web-services::us-east-1"
+  kafka_version = "3.5.1"
+}
+`,
+				ExpectError: regexp.MustCompile(`Cannot downgrade Kafka`),
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_deletionProtection(t *testing.T) {
+	srv := testAccServer(t)
+
+	protected := func(protect bool) string {
+		return testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_instance" "test" {
+  name                = "test"
+  plan                = "ducky"
+  region              = "amazon-web-services::us-east-1"
+  deletion_protection = %v
+  keep_vpc            = true
+}
+`, protect)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := srv.Instance(1); ok {
+				return fmt.Errorf("instance 1 still exists")
+			}
+			for _, r := range srv.Requests() {
+				if r == "DELETE /api/instances/1?keep_vpc=true" {
+					return nil
+				}
+			}
+			return fmt.Errorf("instance was not deleted with keep_vpc=true: %v", srv.Requests())
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: protected(true),
+				Check:  resource.TestCheckResourceAttr("cloudkarafka_instance.test", "deletion_protection", "true"),
+			},
+			{
+				// Removing the resource from the configuration destroys it.
+				Config:      testAccProviderConfig,
+				ExpectError: regexp.MustCompile(`Instance is protected`),
+			},
+			{
+				Config: protected(false),
+				Check: func(*terraform.State) error {
+					if _, ok := srv.Instance(1); !ok {
+						return fmt.Errorf("protected instance was deleted")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,273 @@
+package cloudkarafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"terraform-provider-cloudkarafka/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &quotaResource{}
+	_ resource.ResourceWithConfigure      = &quotaResource{}
+	_ resource.ResourceWithImportState    = &quotaResource{}
+	_ resource.ResourceWithValidateConfig = &quotaResource{}
+)
+
+// NewQuotaResource is a helper function to simplify the provider implementation.
+func NewQuotaResource() resource.Resource {
+	return &quotaResource{}
+}
+
+// quotaResource is the resource implementation.
+type quotaResource struct {
+	client *api.API
+}
+
+type quotaResourceModel struct {
+	InstanceID        types.Int64    `tfsdk:"instance_id"`
+	User              types.String   `tfsdk:"user"`
+	ClientID          types.String   `tfsdk:"client_id"`
+	ProducerByteRate  types.Int64    `tfsdk:"producer_byte_rate"`
+	ConsumerByteRate  types.Int64    `tfsdk:"consumer_byte_rate"`
+	RequestPercentage types.Float64  `tfsdk:"request_percentage"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// quota returns the quota to send to the API.
+func (m *quotaResourceModel) quota() api.Quota {
+	return api.Quota{
+		User:              m.User.ValueString(),
+		ClientId:          m.ClientID.ValueString(),
+		ProducerByteRate:  m.ProducerByteRate.ValueInt64Pointer(),
+		ConsumerByteRate:  m.ConsumerByteRate.ValueInt64Pointer(),
+		RequestPercentage: m.RequestPercentage.ValueFloat64Pointer(),
+	}
+}
+
+// setLimits copies the limits reported by the API into the model.
+func (m *quotaResourceModel) setLimits(q *api.Quota) {
+	m.ProducerByteRate = types.Int64PointerValue(q.ProducerByteRate)
+	m.ConsumerByteRate = types.Int64PointerValue(q.ConsumerByteRate)
+	m.RequestPercentage = types.Float64PointerValue(q.RequestPercentage)
+}
+
+// Metadata returns the data source type name.
+func (r *quotaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_quota"
+}
+
+// Schema defines the schema for the data source.
+func (r *quotaResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a client quota. A quota applies to the clients of a user, to clients with a client ID, " +
+			"or to clients with a client ID of a user. Use `<default>` for the default quota of all users or client IDs.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance where we want to manage the quota.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"user": schema.StringAttribute{
+				Description: "User the quota applies to.",
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"client_id": schema.StringAttribute{
+				Description: "Client ID the quota applies to.",
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"producer_byte_rate": schema.Int64Attribute{
+				Description: "Bytes per second each broker accepts from the producers.",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"consumer_byte_rate": schema.Int64Attribute{
+				Description: "Bytes per second each broker sends to the consumers.",
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"request_percentage": schema.Float64Attribute{
+				Description: "Percentage of a broker thread the clients may use for requests, " +
+					"summed over the network and I/O threads, so it can exceed 100.",
+				Optional:   true,
+				Validators: []validator.Float64{float64validator.AtLeast(0.01)},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks that the quota applies to someone and limits something.
+func (r *quotaResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config quotaResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.User.IsNull() && config.ClientID.IsNull() {
+		resp.Diagnostics.AddError("Missing quota entity", "Set user, client_id or both.")
+	}
+	if config.ProducerByteRate.IsNull() && config.ConsumerByteRate.IsNull() && config.RequestPercentage.IsNull() {
+		resp.Diagnostics.AddError("Missing quota limit",
+			"Set at least one of producer_byte_rate, consumer_byte_rate and request_percentage.")
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *quotaResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.API)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *quotaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan quotaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	err := r.client.CreateQuota(ctx, plan.InstanceID.ValueInt64(), plan.quota())
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating quota", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *quotaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state quotaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	quota, err := r.client.ReadQuota(ctx, state.InstanceID.ValueInt64(), state.User.ValueString(), state.ClientID.ValueString())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read quota", err.Error())
+		return
+	}
+	state.setLimits(quota)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *quotaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan quotaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	err := r.client.UpdateQuota(ctx, plan.InstanceID.ValueInt64(), plan.quota())
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating quota", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *quotaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state quotaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteQuota(ctx, state.InstanceID.ValueInt64(), state.User.ValueString(), state.ClientID.ValueString())
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		resp.Diagnostics.AddError("Error deleting quota", err.Error())
+	}
+}
+
+func (r *quotaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	const format = "<instance_id>/<user>/<client_id>, leave out the user or client ID the quota does not apply to"
+	instanceID, entity, err := splitImportID(req.ID, format)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	user, clientID, ok := strings.Cut(entity, "/")
+	if !ok || (user == "" && clientID == "") {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("expected import ID in the format %s, got %q", format, req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	if user != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user"), user)...)
+	}
+	if clientID != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("client_id"), clientID)...)
+	}
+}
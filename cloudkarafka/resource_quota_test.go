@@ -0,0 +1,147 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccQuotaResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.SetQuota(id, api.Quota{ClientId: "orders-app", ConsumerByteRate: api.Int64(2048)})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_quota" "test" {
+  instance_id        = %d
+  client_id          = "orders-app"
+  consumer_byte_rate = 2048
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_quota.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%d/orders-app", id),
+				ExpectError:   regexp.MustCompile(`format\s+<instance_id>/<user>/<client_id>`),
+			},
+		}, importSteps("cloudkarafka_quota.test", fmt.Sprintf("%d//orders-app", id), config, map[string]string{
+			"instance_id":        fmt.Sprint(id),
+			"client_id":          "orders-app",
+			"consumer_byte_rate": "2048",
+		})...),
+	})
+}
+
+func TestAccQuotaResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	config := func(limits string) string {
+		return testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_quota" "test" {
+  instance_id = %d
+  user        = "alice"
+  %s
+}
+`, id, limits)
+	}
+	// checkServer checks the limits on the server, zero means unset.
+	checkServer := func(producer int64, percentage float64) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			q, ok := srv.Quota(id, "alice", "")
+			if !ok {
+				return fmt.Errorf("quota is missing")
+			}
+			var gotProducer int64
+			var gotPercentage float64
+			if q.ProducerByteRate != nil {
+				gotProducer = *q.ProducerByteRate
+			}
+			if q.RequestPercentage != nil {
+				gotPercentage = *q.RequestPercentage
+			}
+			if gotProducer != producer || gotPercentage != percentage {
+				return fmt.Errorf("server has producer_byte_rate %d and request_percentage %g, want %d and %g",
+					gotProducer, gotPercentage, producer, percentage)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := srv.Quota(id, "alice", ""); ok {
+				return fmt.Errorf("quota was not deleted")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(`producer_byte_rate = 1048576`),
+				Check:  checkServer(1048576, 0),
+			},
+			{
+				Config: config(`
+  producer_byte_rate = 2097152
+  request_percentage = 25.5
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_quota.test", "request_percentage", "25.5"),
+					checkServer(2097152, 25.5),
+				),
+			},
+			{
+				// A limit changed outside Terraform is detected and put back.
+				PreConfig: func() {
+					srv.SetQuota(id, api.Quota{User: "alice", ProducerByteRate: api.Int64(1)})
+				},
+				Config: config(`
+  producer_byte_rate = 2097152
+  request_percentage = 25.5
+`),
+				Check: checkServer(2097152, 25.5),
+			},
+			{
+				Config: config(`producer_byte_rate = 2097152`),
+				Check:  checkServer(2097152, 0),
+			},
+		},
+	})
+}
+
+func TestAccQuotaResource_validation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_quota" "test" {
+  instance_id        = 1
+  producer_byte_rate = 1024
+}
+`,
+				ExpectError: regexp.MustCompile(`Set user, client_id or both`),
+			},
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_quota" "test" {
+  instance_id = 1
+  user        = "alice"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing quota limit`),
+			},
+		},
+	})
+}
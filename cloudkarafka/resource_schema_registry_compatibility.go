@@ -0,0 +1,226 @@
+package cloudkarafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"terraform-provider-cloudkarafka/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &schemaCompatibilityResource{}
+	_ resource.ResourceWithConfigure   = &schemaCompatibilityResource{}
+	_ resource.ResourceWithImportState = &schemaCompatibilityResource{}
+)
+
+// NewSchemaCompatibilityResource is a helper function to simplify the provider implementation.
+func NewSchemaCompatibilityResource() resource.Resource {
+	return &schemaCompatibilityResource{}
+}
+
+// schemaCompatibilityResource is the resource implementation.
+type schemaCompatibilityResource struct {
+	client *api.API
+}
+
+type schemaCompatibilityResourceModel struct {
+	InstanceID types.Int64    `tfsdk:"instance_id"`
+	Subject    types.String   `tfsdk:"subject"`
+	Level      types.String   `tfsdk:"level"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the data source type name.
+func (r *schemaCompatibilityResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_registry_compatibility"
+}
+
+// Schema defines the schema for the data source.
+func (r *schemaCompatibilityResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage the compatibility level of the Schema Registry add-on, globally or for one subject. " +
+			"Destroying a subject level makes the subject follow the global level again, " +
+			"destroying the global level puts back " + api.DefaultCompatibilityLevel + ".",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance with the Schema Registry.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"subject": schema.StringAttribute{
+				Description: "Subject to set the level of. Leave out to set the global level.",
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"level": schema.StringAttribute{
+				Description: "Compatibility level, any of " + strings.Join(api.CompatibilityLevels, ", ") + ".",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.OneOf(api.CompatibilityLevels...)},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *schemaCompatibilityResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.API)
+}
+
+// set sets the planned compatibility level.
+func (r *schemaCompatibilityResource) set(ctx context.Context, plan *schemaCompatibilityResourceModel) error {
+	registry, err := r.client.SchemaRegistry(ctx, plan.InstanceID.ValueInt64())
+	if err != nil {
+		return err
+	}
+	return registry.SetCompatibility(ctx, plan.Subject.ValueString(), plan.Level.ValueString())
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *schemaCompatibilityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan schemaCompatibilityResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if err := r.set(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error setting compatibility level", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *schemaCompatibilityResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state schemaCompatibilityResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry, err := r.client.SchemaRegistry(ctx, state.InstanceID.ValueInt64())
+	if err == nil {
+		var level string
+		level, err = registry.ReadCompatibility(ctx, state.Subject.ValueString())
+		state.Level = types.StringValue(level)
+	}
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read compatibility level", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *schemaCompatibilityResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan schemaCompatibilityResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if err := r.set(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error setting compatibility level", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *schemaCompatibilityResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state schemaCompatibilityResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	registry, err := r.client.SchemaRegistry(ctx, state.InstanceID.ValueInt64())
+	if err == nil {
+		if state.Subject.IsNull() {
+			err = registry.SetCompatibility(ctx, "", api.DefaultCompatibilityLevel)
+		} else {
+			err = registry.ResetCompatibility(ctx, state.Subject.ValueString())
+		}
+	}
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		resp.Diagnostics.AddError("Error resetting compatibility level", err.Error())
+	}
+}
+
+func (r *schemaCompatibilityResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	const format = "<instance_id> for the global level or <instance_id>/<subject>"
+	instance, subject, ok := strings.Cut(req.ID, "/")
+	instanceID, err := parseInstanceID(instance)
+	if err != nil || (ok && subject == "") {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("expected import ID in the format %s, got %q", format, req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	if ok {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("subject"), subject)...)
+	}
+}
@@ -0,0 +1,96 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccSchemaCompatibilityResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test", Username: "abcdefgh", Password: "secret"})
+	srv.EnableSchemaRegistry(id)
+
+	config := func(global, subject string) string {
+		return testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_schema_registry_compatibility" "global" {
+  instance_id = %[1]d
+  level       = %[2]q
+}
+
+resource "cloudkarafka_schema_registry_compatibility" "orders" {
+  instance_id = %[1]d
+  subject     = "orders-value"
+  level       = %[3]q
+}
+`, id, global, subject)
+	}
+	checkServer := func(global, subject string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if got := srv.Compatibility(id, ""); got != global {
+				return fmt.Errorf("global level is %q, want %q", got, global)
+			}
+			if got := srv.Compatibility(id, "orders-value"); got != subject {
+				return fmt.Errorf("subject level is %q, want %q", got, subject)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkServer(api.DefaultCompatibilityLevel, ""),
+		Steps: []resource.TestStep{
+			{
+				Config: config("FULL", "NONE"),
+				Check:  checkServer("FULL", "NONE"),
+			},
+			{
+				Config: config("FULL_TRANSITIVE", "FORWARD"),
+				Check:  checkServer("FULL_TRANSITIVE", "FORWARD"),
+			},
+			{
+				// A level changed outside Terraform is detected and put back.
+				PreConfig: func() {
+					srv.SetCompatibility(id, "orders-value", "NONE")
+				},
+				Config: config("FULL_TRANSITIVE", "FORWARD"),
+				Check:  checkServer("FULL_TRANSITIVE", "FORWARD"),
+			},
+		},
+	})
+}
+
+func TestAccSchemaCompatibilityResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test", Username: "abcdefgh", Password: "secret"})
+	srv.EnableSchemaRegistry(id)
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_schema_registry_compatibility" "test" {
+  instance_id = %d
+  level       = "BACKWARD"
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_schema_registry_compatibility.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%d/", id),
+				ExpectError:   regexp.MustCompile(`format\s+<instance_id>\s+for\s+the\s+global\s+level`),
+			},
+		}, importSteps("cloudkarafka_schema_registry_compatibility.test", fmt.Sprint(id), config, map[string]string{
+			"instance_id": fmt.Sprint(id),
+			"level":       "BACKWARD",
+		})...),
+	})
+}
@@ -0,0 +1,380 @@
+package cloudkarafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"terraform-provider-cloudkarafka/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &schemaSubjectResource{}
+	_ resource.ResourceWithConfigure      = &schemaSubjectResource{}
+	_ resource.ResourceWithImportState    = &schemaSubjectResource{}
+	_ resource.ResourceWithModifyPlan     = &schemaSubjectResource{}
+	_ resource.ResourceWithValidateConfig = &schemaSubjectResource{}
+)
+
+// NewSchemaSubjectResource is a helper function to simplify the provider implementation.
+func NewSchemaSubjectResource() resource.Resource {
+	return &schemaSubjectResource{}
+}
+
+// schemaSubjectResource is the resource implementation.
+type schemaSubjectResource struct {
+	client *api.API
+}
+
+type schemaSubjectResourceModel struct {
+	InstanceID types.Int64            `tfsdk:"instance_id"`
+	Subject    types.String           `tfsdk:"subject"`
+	Schema     types.String           `tfsdk:"schema"`
+	SchemaType types.String           `tfsdk:"schema_type"`
+	References []schemaReferenceModel `tfsdk:"references"`
+	HardDelete types.Bool             `tfsdk:"hard_delete"`
+	SchemaID   types.Int64            `tfsdk:"schema_id"`
+	Version    types.Int64            `tfsdk:"version"`
+	Timeouts   timeouts.Value         `tfsdk:"timeouts"`
+}
+
+type schemaReferenceModel struct {
+	Name    types.String `tfsdk:"name"`
+	Subject types.String `tfsdk:"subject"`
+	Version types.Int64  `tfsdk:"version"`
+}
+
+// schema returns the schema to register.
+func (m *schemaSubjectResourceModel) schema() api.Schema {
+	s := api.Schema{
+		Schema:     m.Schema.ValueString(),
+		SchemaType: m.SchemaType.ValueString(),
+	}
+	for _, ref := range m.References {
+		s.References = append(s.References, api.SchemaReference{
+			Name:    ref.Name.ValueString(),
+			Subject: ref.Subject.ValueString(),
+			Version: ref.Version.ValueInt64(),
+		})
+	}
+	return s
+}
+
+// setSchema copies a version reported by the registry into the model. The
+// registry leaves out the type of Avro schemas, an unset type stays unset.
+func (m *schemaSubjectResourceModel) setSchema(s *api.Schema) {
+	m.Schema = types.StringValue(s.Schema)
+	m.SchemaType = optionalSchemaType(m.SchemaType, s.SchemaType)
+	m.References = []schemaReferenceModel{}
+	for _, ref := range s.References {
+		m.References = append(m.References, schemaReferenceModel{
+			Name:    types.StringValue(ref.Name),
+			Subject: types.StringValue(ref.Subject),
+			Version: types.Int64Value(ref.Version),
+		})
+	}
+}
+
+// sameDefinition reports whether the schema, its type and references are
+// unchanged, so that registering it again keeps the version.
+func (m *schemaSubjectResourceModel) sameDefinition(other *schemaSubjectResourceModel) bool {
+	if !m.Schema.Equal(other.Schema) || !m.SchemaType.Equal(other.SchemaType) || len(m.References) != len(other.References) {
+		return false
+	}
+	for n, ref := range m.References {
+		o := other.References[n]
+		if !ref.Name.Equal(o.Name) || !ref.Subject.Equal(o.Subject) || !ref.Version.Equal(o.Version) {
+			return false
+		}
+	}
+	return true
+}
+
+// optionalSchemaType returns the type reported by the registry, or current
+// when it is unset and the registry reports Avro.
+func optionalSchemaType(current types.String, schemaType string) types.String {
+	if schemaType == "" {
+		schemaType = "AVRO"
+	}
+	if current.IsNull() && schemaType == "AVRO" {
+		return current
+	}
+	return types.StringValue(schemaType)
+}
+
+// Metadata returns the data source type name.
+func (r *schemaSubjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_registry_subject"
+}
+
+// Schema defines the schema for the data source.
+func (r *schemaSubjectResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Register a schema under a subject of the Schema Registry add-on. The registry is reached with the " +
+			"instance credentials. Changing the schema registers a new version, which must be compatible with the " +
+			"earlier ones. Versions registered outside Terraform are left alone, a deleted version is registered again.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance with the Schema Registry.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"subject": schema.StringAttribute{
+				Description: "Name of the subject, e.g. <topic>-value.",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				Description: "The schema definition. Use file() to read it from a file.",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+			"schema_type": schema.StringAttribute{
+				Description: "Format of the schema, AVRO, JSON or PROTOBUF. Defaults to AVRO.",
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.OneOf(api.SchemaTypes...)},
+			},
+			"hard_delete": schema.BoolAttribute{
+				Description: "Permanently delete the subject on destroy. By default it is soft deleted, " +
+					"and registering a schema under it again reuses the old schema ids.",
+				Optional: true,
+			},
+			"schema_id": schema.Int64Attribute{
+				Description: "Registry wide id of the schema.",
+				Computed:    true,
+			},
+			"version": schema.Int64Attribute{
+				Description: "Version of the schema under the subject.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"references": schema.ListNestedBlock{
+				Description: "Schemas in other subjects that the schema refers to.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name the schema refers to the other schema by: a type name for Avro, " +
+								"a URL for JSON, an import path for Protobuf.",
+							Required: true,
+						},
+						"subject": schema.StringAttribute{
+							Description: "Subject of the referenced schema.",
+							Required:    true,
+						},
+						"version": schema.Int64Attribute{
+							Description: "Version of the referenced schema.",
+							Required:    true,
+							Validators:  []validator.Int64{int64validator.AtLeast(1)},
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks that Avro and JSON schemas are JSON.
+func (r *schemaSubjectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config schemaSubjectResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Schema.IsUnknown() || config.Schema.IsNull() || config.SchemaType.IsUnknown() {
+		return
+	}
+	if config.SchemaType.ValueString() == "PROTOBUF" {
+		return
+	}
+	if !json.Valid([]byte(config.Schema.ValueString())) {
+		resp.Diagnostics.AddAttributeError(path.Root("schema"), "Invalid schema",
+			"Avro and JSON schemas must be valid JSON, set schema_type = \"PROTOBUF\" for Protobuf schemas.")
+	}
+}
+
+// ModifyPlan keeps the schema id and version when the schema does not
+// change.
+func (r *schemaSubjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var plan, state schemaSubjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !plan.InstanceID.Equal(state.InstanceID) || !plan.Subject.Equal(state.Subject) || !plan.sameDefinition(&state) {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("schema_id"), state.SchemaID)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("version"), state.Version)...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *schemaSubjectResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.API)
+}
+
+// register registers the planned schema and stores its id and version in
+// the plan.
+func (r *schemaSubjectResource) register(ctx context.Context, plan *schemaSubjectResourceModel) error {
+	registry, err := r.client.SchemaRegistry(ctx, plan.InstanceID.ValueInt64())
+	if err != nil {
+		return err
+	}
+	registered, err := registry.RegisterSchema(ctx, plan.Subject.ValueString(), plan.schema())
+	if err != nil {
+		return err
+	}
+	plan.SchemaID = types.Int64Value(registered.Id)
+	plan.Version = types.Int64Value(registered.Version)
+	return nil
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *schemaSubjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan schemaSubjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if err := r.register(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error registering schema", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *schemaSubjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state schemaSubjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry, err := r.client.SchemaRegistry(ctx, state.InstanceID.ValueInt64())
+	if err == nil {
+		var current *api.Schema
+		// An imported subject has no version yet and reads the latest.
+		current, err = registry.ReadSchema(ctx, state.Subject.ValueString(), state.Version.ValueInt64())
+		if err == nil {
+			// The registry may reformat the schema, keep it as written
+			// while the registered schema stays the same.
+			if state.SchemaID.ValueInt64() != current.Id {
+				state.setSchema(current)
+			}
+			state.SchemaID = types.Int64Value(current.Id)
+			state.Version = types.Int64Value(current.Version)
+		}
+	}
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read schema", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *schemaSubjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan schemaSubjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if err := r.register(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error registering schema", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *schemaSubjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state schemaSubjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	registry, err := r.client.SchemaRegistry(ctx, state.InstanceID.ValueInt64())
+	if err == nil {
+		err = registry.DeleteSubject(ctx, state.Subject.ValueString(), state.HardDelete.ValueBool())
+	}
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		resp.Diagnostics.AddError("Error deleting subject", err.Error())
+	}
+}
+
+func (r *schemaSubjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	instanceID, subject, err := splitImportID(req.ID, "<instance_id>/<subject>")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("subject"), subject)...)
+}
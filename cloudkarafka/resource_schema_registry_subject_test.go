@@ -0,0 +1,175 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+const (
+	testAccKeySchema     = `{"type":"record","name":"OrderKey","fields":[{"name":"id","type":"string"}]}`
+	testAccValueSchemaV1 = `{"type":"record","name":"Order","fields":[{"name":"key","type":"OrderKey"}]}`
+	testAccValueSchemaV2 = `{"type":"record","name":"Order","fields":[{"name":"key","type":"OrderKey"},{"name":"note","type":["null","string"],"default":null}]}`
+)
+
+func TestAccSchemaSubjectResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test", Username: "abcdefgh", Password: "secret"})
+	srv.EnableSchemaRegistry(id)
+
+	config := func(value string) string {
+		return testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_schema_registry_subject" "key" {
+  instance_id = %[1]d
+  subject     = "order-key"
+  schema      = %[2]q
+  hard_delete = true
+}
+
+resource "cloudkarafka_schema_registry_subject" "value" {
+  instance_id = %[1]d
+  subject     = "orders-value"
+  schema      = %[3]q
+
+  references {
+    name    = "OrderKey"
+    subject = cloudkarafka_schema_registry_subject.key.subject
+    version = cloudkarafka_schema_registry_subject.key.version
+  }
+}
+
+data "cloudkarafka_schema_registry_subject" "value" {
+  instance_id = %[1]d
+  subject     = cloudkarafka_schema_registry_subject.value.subject
+  version     = cloudkarafka_schema_registry_subject.value.version
+}
+`, id, testAccKeySchema, value)
+	}
+	checkVersions := func(subject string, want ...int64) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if got := srv.Versions(id, subject); !reflect.DeepEqual(got, want) {
+				return fmt.Errorf("subject %s has versions %v, want %v", subject, got, want)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if srv.SubjectExists(id, "order-key") {
+				return fmt.Errorf("hard deleted subject still exists")
+			}
+			if v := srv.Versions(id, "orders-value"); len(v) > 0 || !srv.SubjectExists(id, "orders-value") {
+				return fmt.Errorf("subject was not soft deleted, live versions %v", v)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(testAccValueSchemaV1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_schema_registry_subject.value", "version", "1"),
+					resource.TestCheckResourceAttr("cloudkarafka_schema_registry_subject.value", "schema_id", "2"),
+					resource.TestCheckResourceAttr("data.cloudkarafka_schema_registry_subject.value", "schema_id", "2"),
+					resource.TestCheckResourceAttr("data.cloudkarafka_schema_registry_subject.value", "schema_type", "AVRO"),
+					resource.TestCheckResourceAttr("data.cloudkarafka_schema_registry_subject.value", "references.0.subject", "order-key"),
+					checkVersions("orders-value", 1),
+				),
+			},
+			{
+				Config: config(testAccValueSchemaV2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_schema_registry_subject.value", "version", "2"),
+					resource.TestCheckResourceAttr("data.cloudkarafka_schema_registry_subject.value", "schema", testAccValueSchemaV2),
+					checkVersions("orders-value", 1, 2),
+				),
+			},
+			{
+				// Versions registered outside Terraform are left alone.
+				PreConfig: func() {
+					srv.RegisterSchema(id, "order-key", api.Schema{Schema: `{"type":"string"}`})
+				},
+				Config: config(testAccValueSchemaV2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_schema_registry_subject.key", "version", "1"),
+					checkVersions("order-key", 1, 2),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSchemaSubjectResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test", Username: "abcdefgh", Password: "secret"})
+	srv.EnableSchemaRegistry(id)
+	const proto = `syntax = "proto3"; message Order { string id = 1; }`
+	srv.RegisterSchema(id, "orders-value", api.Schema{Schema: proto, SchemaType: "PROTOBUF"})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_schema_registry_subject" "test" {
+  instance_id = %d
+  subject     = "orders-value"
+  schema      = %q
+  schema_type = "PROTOBUF"
+}
+`, id, proto)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_schema_registry_subject.test",
+				ImportState:   true,
+				ImportStateId: "orders-value",
+				ExpectError:   regexp.MustCompile(`format\s+<instance_id>/<subject>`),
+			},
+		}, importSteps("cloudkarafka_schema_registry_subject.test", fmt.Sprintf("%d/orders-value", id), config, map[string]string{
+			"instance_id": fmt.Sprint(id),
+			"subject":     "orders-value",
+			"schema":      proto,
+			"schema_type": "PROTOBUF",
+			"schema_id":   "1",
+			"version":     "1",
+		})...),
+	})
+}
+
+func TestAccSchemaSubjectResource_validation(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test", Username: "abcdefgh", Password: "secret"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_schema_registry_subject" "test" {
+  instance_id = 1
+  subject     = "orders-value"
+  schema      = "syntax = \"proto3\";"
+}
+`,
+				ExpectError: regexp.MustCompile(`Avro and JSON schemas must be valid JSON`),
+			},
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_schema_registry_subject" "test" {
+  instance_id = %d
+  subject     = "orders-value"
+  schema      = "{\"type\":\"string\"}"
+}
+`, id),
+				ExpectError: regexp.MustCompile(`has no Schema Registry`),
+			},
+		},
+	})
+}
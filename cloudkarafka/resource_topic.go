@@ -2,9 +2,16 @@ package cloudkarafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"terraform-provider-cloudkarafka/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -15,15 +22,17 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &topicResource{}
-	_ resource.ResourceWithConfigure   = &topicResource{}
-	_ resource.ResourceWithImportState = &topicResource{}
+	_ resource.Resource                   = &topicResource{}
+	_ resource.ResourceWithConfigure      = &topicResource{}
+	_ resource.ResourceWithImportState    = &topicResource{}
+	_ resource.ResourceWithValidateConfig = &topicResource{}
 )
 
 // NewOrderResource is a helper function to simplify the provider implementation.
@@ -37,11 +46,14 @@ type topicResource struct {
 }
 
 type topicResourceModel struct {
-	InstanceID        types.Int64              `tfsdk:"instance_id"`
-	Name              types.String             `tfsdk:"name"`
-	Partitions        types.Int64              `tfsdk:"partitions"`
-	ReplicationFactor types.Int64              `tfsdk:"replication_factor"`
-	Config            topicConfigResourceModel `tfsdk:"config"`
+	InstanceID        types.Int64               `tfsdk:"instance_id"`
+	Name              types.String              `tfsdk:"name"`
+	Partitions        types.Int64               `tfsdk:"partitions"`
+	ReplicationFactor types.Int64               `tfsdk:"replication_factor"`
+	Config            *topicConfigResourceModel `tfsdk:"config"`
+	ExtraConfig       map[string]types.String   `tfsdk:"extra_config"`
+	AllowDecrease     types.Bool                `tfsdk:"allow_partition_decrease_by_recreate"`
+	Timeouts          timeouts.Value            `tfsdk:"timeouts"`
 }
 
 type topicConfigResourceModel struct {
@@ -77,15 +89,170 @@ func (me topicConfigResourceModel) AsHash() map[string]interface{} {
 
 }
 
+// readFrom reconciles the settings with the topic config reported by the
+// server. Only settings that are set are refreshed, the others follow the
+// defaults of the instance. After import, defaults holds those defaults and
+// every setting that differs from its default is taken over.
+func (me *topicConfigResourceModel) readFrom(config api.Hash, defaults map[string]string) error {
+	var err error
+	if me.CleanupPolicy, err = readTopicCleanupPolicy(me.CleanupPolicy, config, defaults); err != nil {
+		return err
+	}
+	if me.MinInsyncReplicas, err = readTopicInt64(me.MinInsyncReplicas, config, defaults, "min.insync.replicas"); err != nil {
+		return err
+	}
+	if me.RetentionBytes, err = readTopicInt64(me.RetentionBytes, config, defaults, "retention.bytes"); err != nil {
+		return err
+	}
+	if me.RetentionMs, err = readTopicInt64(me.RetentionMs, config, defaults, "retention.ms"); err != nil {
+		return err
+	}
+	if me.DeleteRetentionMs, err = readTopicInt64(me.DeleteRetentionMs, config, defaults, "delete.retention.ms"); err != nil {
+		return err
+	}
+	if me.SegmentBytes, err = readTopicInt64(me.SegmentBytes, config, defaults, "segment.bytes"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isNull reports whether none of the settings are set.
+func (me topicConfigResourceModel) isNull() bool {
+	return me.CleanupPolicy.IsNull() && me.MinInsyncReplicas.IsNull() &&
+		me.RetentionBytes.IsNull() && me.RetentionMs.IsNull() &&
+		me.DeleteRetentionMs.IsNull() && me.SegmentBytes.IsNull()
+}
+
+// topicConfigKeys lists the topic settings that have a dedicated attribute in config.
+var topicConfigKeys = []string{
+	"cleanup.policy",
+	"min.insync.replicas",
+	"retention.bytes",
+	"retention.ms",
+	"delete.retention.ms",
+	"segment.bytes",
+}
+
+// adoptTopicSetting reports whether an unset setting with the server value s
+// is taken over, which only happens after import for non-default values.
+func adoptTopicSetting(defaults map[string]string, key, s string) bool {
+	return defaults != nil && s != defaults[key]
+}
+
+func readTopicInt64(current types.Int64, config api.Hash, defaults map[string]string, key string) (types.Int64, error) {
+	v, ok := config[key]
+	if !ok {
+		// Settings at the default may be left out, which is no change.
+		return current, nil
+	}
+	s := api.ValueString(v)
+	if current.IsNull() && !adoptTopicSetting(defaults, key, s) {
+		return current, nil
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return current, fmt.Errorf("invalid value %q for %s", s, key)
+	}
+	return types.Int64Value(i), nil
+}
+
+func readTopicCleanupPolicy(current types.String, config api.Hash, defaults map[string]string) (types.String, error) {
+	v, ok := config["cleanup.policy"]
+	if !ok {
+		return current, nil
+	}
+	s := api.ValueString(v)
+	if current.IsNull() && !adoptTopicSetting(defaults, "cleanup.policy", s) {
+		return current, nil
+	}
+	// The policy is a list, keep the configured spelling when it means the same.
+	if !current.IsNull() && sameCleanupPolicy(current.ValueString(), s) {
+		return current, nil
+	}
+	return types.StringValue(s), nil
+}
+
+func sameCleanupPolicy(a, b string) bool {
+	split := func(s string) map[string]bool {
+		set := make(map[string]bool)
+		for _, p := range strings.Split(strings.Trim(s, "[]"), ",") {
+			set[strings.ToLower(strings.TrimSpace(p))] = true
+		}
+		return set
+	}
+	return reflect.DeepEqual(split(a), split(b))
+}
+
+// configHash merges config and extra_config into the hash sent to the API.
+func (me topicResourceModel) configHash() api.Hash {
+	config := make(api.Hash)
+	if me.Config != nil {
+		config = me.Config.AsHash()
+	}
+	for k, v := range me.ExtraConfig {
+		config[k] = v.ValueString()
+	}
+	return config
+}
+
+// adoptExtraConfig fills extra_config after import with the settings that
+// have no dedicated attribute and differ from their default.
+func (me *topicResourceModel) adoptExtraConfig(config api.Hash, defaults map[string]string) {
+	for k, v := range config {
+		s := api.ValueString(v)
+		if d, ok := defaults[k]; (ok && s == d) || isTopicConfigKey(k) {
+			continue
+		}
+		if me.ExtraConfig == nil {
+			me.ExtraConfig = make(map[string]types.String)
+		}
+		me.ExtraConfig[k] = types.StringValue(s)
+	}
+}
+
+func isTopicConfigKey(key string) bool {
+	for _, k := range topicConfigKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// removedConfig returns the settings that are set in the state but no longer
+// in the plan.
+func (me topicResourceModel) removedConfig(plan topicResourceModel) []string {
+	var removed []string
+	current := plan.configHash()
+	for k := range me.configHash() {
+		if _, ok := current[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	return removed
+}
+
+// topicDefaults returns the values topics on the instance get for settings
+// they are created without.
+func (r *topicResource) topicDefaults(ctx context.Context, instanceID int64) (map[string]string, error) {
+	config, err := r.client.ReadConfig(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return api.TopicDefaults(config.Get), nil
+}
+
 // Metadata returns the data source type name.
 func (r *topicResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_topic"
 }
 
 // Schema defines the schema for the data source.
-func (r *topicResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *topicResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manage a topic.",
+		Description: "Manage a topic. Only the settings set in config are compared with the server, the others follow the defaults of the instance. " +
+			"Removing a setting from config puts it back to that default. " +
+			"When imported, config and extra_config are filled from the settings that differ from the default of the instance.",
 		Attributes: map[string]schema.Attribute{
 			"instance_id": schema.Int64Attribute{
 				Description: "Id of the instance where we want to manage the topic.",
@@ -99,9 +266,16 @@ func (r *topicResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 			"partitions": schema.Int64Attribute{
-				Description: "Number of partitions for the topic.",
+				Description: "Number of partitions for the topic. Can only be increased in place.",
 				Required:    true,
 				Validators:  []validator.Int64{int64validator.AtLeast(1)},
+				PlanModifiers: []planmodifier.Int64{
+					partitionDecrease(path.Root("allow_partition_decrease_by_recreate")),
+				},
+			},
+			"allow_partition_decrease_by_recreate": schema.BoolAttribute{
+				Description: "Recreate the topic when partitions is lowered, instead of failing the plan. All data in the topic is lost.",
+				Optional:    true,
 			},
 			"replication_factor": schema.Int64Attribute{
 				Description: "Replication factor for the topic.",
@@ -118,7 +292,7 @@ func (r *topicResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					"cleanup_policy": schema.StringAttribute{
 						Description: "Delete or compact when records hit their retention.",
 						Optional:    true,
-						Validators:  []validator.String{stringvalidator.OneOfCaseInsensitive("delete", "compact")},
+						Validators:  []validator.String{stringvalidator.OneOfCaseInsensitive("delete", "compact", "compact,delete", "delete,compact")},
 					},
 					"delete_retention_ms": schema.Int64Attribute{
 						Description: "Delete or compact when records hit their retention.",
@@ -143,10 +317,45 @@ func (r *topicResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					},
 				},
 			},
+			"extra_config": schema.MapAttribute{
+				Description: "Any other topic configuration, keyed by its Kafka name, e.g. `compression.type`. " +
+					"Only the keys set here are compared with the server, other settings changed outside Terraform do not show as drift. " +
+					"Removing a key puts the setting back to its default.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`),
+						"must be a Kafka topic config name",
+					)),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+// ValidateConfig rejects extra_config keys that have a dedicated attribute.
+func (r *topicResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var extra map[string]types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("extra_config"), &extra)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, k := range topicConfigKeys {
+		if _, ok := extra[k]; ok {
+			resp.Diagnostics.AddAttributeError(path.Root("extra_config").AtMapKey(k), "Conflicting topic config",
+				fmt.Sprintf("Use config.%s instead of setting %s in extra_config.", strings.ReplaceAll(k, ".", "_"), k))
+		}
+	}
+}
+
 // Configure adds the provider configured client to the data source.
 func (r *topicResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -163,13 +372,22 @@ func (r *topicResource) Create(ctx context.Context, req resource.CreateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	createRequest := api.Topic{
 		Name:       plan.Name.ValueString(),
 		Partitions: plan.Partitions.ValueInt64(),
 		Replicas:   plan.ReplicationFactor.ValueInt64(),
-		Config:     plan.Config.AsHash(),
+		Config:     plan.configHash(),
 	}
-	err := r.client.CreateTopic(plan.InstanceID.ValueInt64(), createRequest)
+	err := r.client.CreateTopic(ctx, plan.InstanceID.ValueInt64(), createRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating topic", err.Error())
 		return
@@ -188,30 +406,48 @@ func (r *topicResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	topic, err := r.client.ReadTopic(state.InstanceID.ValueInt64(), state.Name.ValueString())
+	topic, err := r.client.ReadTopic(ctx, state.InstanceID.ValueInt64(), state.Name.ValueString())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read topic state", err.Error())
 		return
 	}
 	state.Partitions = types.Int64Value(topic.Partitions)
 	state.ReplicationFactor = types.Int64Value(topic.Replicas)
-	if v, ok := topic.Config["cleanup.policy"]; ok {
-		state.Config.CleanupPolicy = types.StringValue(v.(string))
+	var defaults map[string]string
+	imported, diags := takeImported(ctx, req.Private, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	if imported {
+		defaults, err = r.topicDefaults(ctx, state.InstanceID.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read topic defaults", err.Error())
+			return
+		}
 	}
-	if v, ok := topic.Config["retention.ms"]; ok {
-		state.Config.RetentionMs = types.Int64Value(int64(v.(float64)))
+	var config topicConfigResourceModel
+	if state.Config != nil {
+		config = *state.Config
 	}
-	if v, ok := topic.Config["retention.bytes"]; ok {
-		state.Config.RetentionBytes = types.Int64Value(int64(v.(float64)))
+	if err := config.readFrom(topic.Config, defaults); err != nil {
+		resp.Diagnostics.AddError("Failed to read topic config", err.Error())
+		return
 	}
-	if v, ok := topic.Config["segment.bytes"]; ok {
-		state.Config.SegmentBytes = types.Int64Value(int64(v.(float64)))
+	if state.Config != nil || !config.isNull() {
+		state.Config = &config
 	}
-	if v, ok := topic.Config["min.insync.replicas"]; ok {
-		state.Config.MinInsyncReplicas = types.Int64Value(int64(v.(float64)))
+	if imported {
+		state.adoptExtraConfig(topic.Config, defaults)
 	}
-	if v, ok := topic.Config["delete.retention.ms"]; ok {
-		state.Config.DeleteRetentionMs = types.Int64Value(int64(v.(float64)))
+	for k := range state.ExtraConfig {
+		if v, ok := topic.Config[k]; ok {
+			state.ExtraConfig[k] = types.StringValue(api.ValueString(v))
+		} else {
+			delete(state.ExtraConfig, k)
+		}
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -222,15 +458,44 @@ func (r *topicResource) Read(ctx context.Context, req resource.ReadRequest, resp
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *topicResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var (
-		plan *topicResourceModel
+		plan  *topicResourceModel
+		state *topicResourceModel
 	)
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.client.UpdateTopic(plan.InstanceID.ValueInt64(), plan.Name.ValueString(), api.UpdateTopicRequest{
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// The update merges the config, so settings removed from the
+	// configuration are sent with their default, or without a value when the
+	// default is not known, to reset them.
+	config := plan.configHash()
+	if removed := state.removedConfig(*plan); len(removed) > 0 {
+		defaults, err := r.topicDefaults(ctx, plan.InstanceID.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Error updating topic", err.Error())
+			return
+		}
+		for _, k := range removed {
+			if v, ok := defaults[k]; ok {
+				config[k] = v
+			} else {
+				config[k] = nil
+			}
+		}
+	}
+	err := r.client.UpdateTopic(ctx, plan.InstanceID.ValueInt64(), plan.Name.ValueString(), api.UpdateTopicRequest{
 		Partitions: plan.Partitions.ValueInt64(),
-		Config:     plan.Config.AsHash(),
+		Config:     config,
 	})
 	if err != nil {
 		resp.Diagnostics.AddError("Error updating topic", err.Error())
@@ -251,7 +516,15 @@ func (r *topicResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := r.client.DeleteTopic(state.InstanceID.ValueInt64(), state.Name.ValueString())
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteTopic(ctx, state.InstanceID.ValueInt64(), state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting topic", err.Error())
 		return
@@ -261,6 +534,12 @@ func (r *topicResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (r *topicResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	instanceID, name, err := splitImportID(req.ID, "<instance_id>/<topic>")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(markImported(ctx, resp.Private)...)
 }
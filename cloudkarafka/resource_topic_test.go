@@ -0,0 +1,314 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+	"terraform-provider-cloudkarafka/api/apitest"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestTopicResourceModelConfigHash(t *testing.T) {
+	model := topicResourceModel{
+		Config: &topicConfigResourceModel{
+			CleanupPolicy: types.StringValue("compact,delete"),
+			RetentionMs:   types.Int64Value(86400000),
+		},
+		ExtraConfig: map[string]types.String{
+			"compression.type":      types.StringValue("zstd"),
+			"min.compaction.lag.ms": types.StringValue("60000"),
+		},
+	}
+	want := api.Hash{
+		"cleanup.policy":        "compact,delete",
+		"retention.ms":          int64(86400000),
+		"compression.type":      "zstd",
+		"min.compaction.lag.ms": "60000",
+	}
+	if got := model.configHash(); !reflect.DeepEqual(got, want) {
+		t.Errorf("configHash() = %v, want %v", got, want)
+	}
+}
+
+func TestTopicConfigResourceModelReadFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  topicConfigResourceModel
+		server   api.Hash
+		defaults map[string]string
+		want     topicConfigResourceModel
+	}{
+		{
+			name:    "unset settings are left unset",
+			current: topicConfigResourceModel{},
+			server: api.Hash{
+				"cleanup.policy":  "compact",
+				"retention.ms":    float64(3600000),
+				"segment.bytes":   "1073741824",
+				"retention.bytes": float64(-1),
+			},
+			want: topicConfigResourceModel{},
+		},
+		{
+			name:    "imported settings at the instance default are left unset",
+			current: topicConfigResourceModel{},
+			server: api.Hash{
+				"cleanup.policy": "delete",
+				"retention.ms":   float64(3600000),
+			},
+			defaults: map[string]string{
+				"cleanup.policy": "delete",
+				"retention.ms":   "3600000",
+			},
+			want: topicConfigResourceModel{},
+		},
+		{
+			name:    "imported settings that differ from the default are taken over",
+			current: topicConfigResourceModel{},
+			server: api.Hash{
+				"cleanup.policy": "compact",
+				"retention.ms":   "86400000",
+			},
+			defaults: map[string]string{
+				"cleanup.policy": "delete",
+				"retention.ms":   "604800000",
+			},
+			want: topicConfigResourceModel{
+				CleanupPolicy: types.StringValue("compact"),
+				RetentionMs:   types.Int64Value(86400000),
+			},
+		},
+		{
+			name: "explicit defaults are kept",
+			current: topicConfigResourceModel{
+				RetentionMs:    types.Int64Value(604800000),
+				RetentionBytes: types.Int64Value(1024),
+			},
+			server: api.Hash{
+				"retention.ms":    float64(604800000),
+				"retention.bytes": "2048",
+			},
+			want: topicConfigResourceModel{
+				RetentionMs:    types.Int64Value(604800000),
+				RetentionBytes: types.Int64Value(2048),
+			},
+		},
+		{
+			name: "cleanup policy order is ignored",
+			current: topicConfigResourceModel{
+				CleanupPolicy: types.StringValue("compact,delete"),
+			},
+			server: api.Hash{"cleanup.policy": "[delete, compact]"},
+			want: topicConfigResourceModel{
+				CleanupPolicy: types.StringValue("compact,delete"),
+			},
+		},
+		{
+			name: "settings left out by the server are kept",
+			current: topicConfigResourceModel{
+				SegmentBytes: types.Int64Value(1073741824),
+			},
+			server: api.Hash{},
+			want: topicConfigResourceModel{
+				SegmentBytes: types.Int64Value(1073741824),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.current
+			if err := got.readFrom(tt.server, tt.defaults); err != nil {
+				t.Fatalf("readFrom() failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readFrom() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccTopicResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.AddTopic(id, api.Topic{
+		Name:       "orders",
+		Partitions: 3,
+		Replicas:   2,
+		Config: api.Hash{
+			"cleanup.policy":   "delete",
+			"compression.type": "producer",
+			"flush.messages":   "1000",
+			"retention.ms":     "86400000",
+			"segment.bytes":    "1073741824",
+		},
+	})
+
+	config := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_topic" "test" {
+  instance_id        = %d
+  name               = "orders"
+  partitions         = 3
+  replication_factor = 2
+  config = {
+    retention_ms = 86400000
+  }
+  extra_config = {
+    "flush.messages" = "1000"
+  }
+}
+`, id)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_topic.test",
+				ImportState:   true,
+				ImportStateId: "orders",
+				ExpectError:   regexp.MustCompile(`format <instance_id>/<topic>`),
+			},
+		}, importSteps("cloudkarafka_topic.test", fmt.Sprintf("%d/orders", id), config, map[string]string{
+			"instance_id":                 fmt.Sprint(id),
+			"name":                        "orders",
+			"partitions":                  "3",
+			"replication_factor":          "2",
+			"config.retention_ms":         "86400000",
+			"config.cleanup_policy":       "",
+			"config.segment_bytes":        "",
+			"extra_config.%":              "1",
+			"extra_config.flush.messages": "1000",
+		})...),
+	})
+}
+
+func TestAccTopicResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			for _, name := range []string{"orders", "events"} {
+				if _, ok := srv.Topic(id, name); ok {
+					return fmt.Errorf("topic %s still exists", name)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_topic" "test" {
+  instance_id        = %d
+  name               = "orders"
+  partitions         = 1
+  replication_factor = 1
+  config = {
+    retention_ms  = 86400000
+    segment_bytes = 1073741824
+  }
+}
+`, id),
+				// The server leaves out segment.bytes, it is at the default.
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_topic.test", "partitions", "1"),
+					resource.TestCheckResourceAttr("cloudkarafka_topic.test", "config.retention_ms", "86400000"),
+					resource.TestCheckResourceAttr("cloudkarafka_topic.test", "config.segment_bytes", "1073741824"),
+					resource.TestCheckNoResourceAttr("cloudkarafka_topic.test", "config.cleanup_policy"),
+				),
+			},
+			{
+				// A transient error while refreshing is retried.
+				PreConfig: func() {
+					srv.Inject(apitest.Fault{Method: "GET", Path: fmt.Sprintf("/api/instances/%d/topics", id), Status: 503, RetryAfter: "0"})
+				},
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_topic" "test" {
+  instance_id        = %d
+  name               = "orders"
+  partitions         = 2
+  replication_factor = 1
+  config = {
+    retention_ms  = 86400000
+    segment_bytes = 1073741824
+  }
+  extra_config = {
+    "compression.type" = "zstd"
+    "flush.messages"   = "1000"
+  }
+}
+`, id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_topic.test", "partitions", "2"),
+					resource.TestCheckResourceAttr("cloudkarafka_topic.test", "extra_config.compression.type", "zstd"),
+					func(*terraform.State) error {
+						topic, _ := srv.Topic(id, "orders")
+						if topic.Partitions != 2 || topic.Config["compression.type"] != "zstd" {
+							return fmt.Errorf("unexpected topic on server: %+v", topic)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Settings left out follow the broker config of the instance,
+				// and removing one from config or extra_config resets it.
+				PreConfig: func() {
+					srv.SetConfig(id, map[string]string{
+						"log.retention.ms":  "3600000",
+						"log.segment.bytes": "536870912",
+					})
+				},
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_topic" "test" {
+  instance_id        = %[1]d
+  name               = "orders"
+  partitions         = 2
+  replication_factor = 1
+  extra_config = {
+    "compression.type" = "zstd"
+  }
+}
+
+resource "cloudkarafka_topic" "events" {
+  instance_id        = %[1]d
+  name               = "events"
+  partitions         = 1
+  replication_factor = 1
+}
+`, id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("cloudkarafka_topic.test", "config.retention_ms"),
+					resource.TestCheckNoResourceAttr("cloudkarafka_topic.events", "config.segment_bytes"),
+					func(*terraform.State) error {
+						topic, _ := srv.Topic(id, "orders")
+						if _, ok := topic.Config["retention.ms"]; ok {
+							return fmt.Errorf("retention.ms was not reset: %+v", topic.Config)
+						}
+						if _, ok := topic.Config["flush.messages"]; ok {
+							return fmt.Errorf("flush.messages was not reset: %+v", topic.Config)
+						}
+						if topic, _ := srv.Topic(id, "events"); len(topic.Config) > 0 {
+							return fmt.Errorf("events has overrides: %+v", topic.Config)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:                         "cloudkarafka_topic.test",
+				ImportState:                          true,
+				ImportStateId:                        fmt.Sprintf("%d/orders", id),
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+		},
+	})
+}
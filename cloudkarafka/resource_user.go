@@ -2,9 +2,13 @@ package cloudkarafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"terraform-provider-cloudkarafka/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -19,9 +23,10 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &userResource{}
-	_ resource.ResourceWithConfigure   = &userResource{}
-	_ resource.ResourceWithImportState = &userResource{}
+	_ resource.Resource                   = &userResource{}
+	_ resource.ResourceWithConfigure      = &userResource{}
+	_ resource.ResourceWithImportState    = &userResource{}
+	_ resource.ResourceWithValidateConfig = &userResource{}
 )
 
 // NewOrderResource is a helper function to simplify the provider implementation.
@@ -35,9 +40,56 @@ type userResource struct {
 }
 
 type userResourceModel struct {
-	InstanceID types.Int64  `tfsdk:"instance_id"`
-	Name       types.String `tfsdk:"name"`
-	Type       types.String `tfsdk:"type"`
+	InstanceID  types.Int64             `tfsdk:"instance_id"`
+	Name        types.String            `tfsdk:"name"`
+	Type        types.String            `tfsdk:"type"`
+	Password    types.String            `tfsdk:"password"`
+	Certificate types.String            `tfsdk:"certificate"`
+	PrivateKey  types.String            `tfsdk:"private_key"`
+	Keepers     map[string]types.String `tfsdk:"keepers"`
+	Timeouts    timeouts.Value          `tfsdk:"timeouts"`
+}
+
+// setCredentials copies the credentials returned when the user was created
+// or rotated. Credentials the user type does not have are left unset.
+func (m *userResourceModel) setCredentials(user *api.User) {
+	m.Password = optionalString(user.Password)
+	m.Certificate = optionalString(user.Certificate)
+	m.PrivateKey = optionalString(user.PrivateKey)
+}
+
+// sameKeepers reports whether a and b hold the same values. Unset and empty
+// are the same.
+func sameKeepers(a, b map[string]types.String) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !v.Equal(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// optionalString returns s, or null if it is empty.
+func optionalString(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// userType returns the type reported by the API, users without one are sasl
+// users. The configured spelling is kept while it is the same type.
+func userType(current types.String, reported string) types.String {
+	if reported == "" {
+		reported = "sasl"
+	}
+	if !current.IsNull() && !current.IsUnknown() && strings.EqualFold(current.ValueString(), reported) {
+		return current
+	}
+	return types.StringValue(reported)
 }
 
 // Metadata returns the data source type name.
@@ -46,9 +98,11 @@ func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest,
 }
 
 // Schema defines the schema for the data source.
-func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *userResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manage a user.",
+		Description: "Manage a user. The password of sasl users, and the certificate and key of ssl users, " +
+			"are only returned by the API when the user is created or its credentials rotated. " +
+			"Imported users have no credentials in state until they are rotated.",
 		Attributes: map[string]schema.Attribute{
 			"instance_id": schema.Int64Attribute{
 				Description: "Id of the instance where we want to manage the user.",
@@ -62,17 +116,69 @@ func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 			"type": schema.StringAttribute{
-				Description: "Type of user, either sasl or ssl.",
+				Description: "Type of user, either sasl or ssl. Defaults to sasl.",
 				Optional:    true,
+				Computed:    true,
 				Validators:  []validator.String{stringvalidator.OneOfCaseInsensitive("sasl", "ssl")},
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"password": schema.StringAttribute{
+				Description: "Password of a sasl user. Generated unless set, changing it sets the new password in place.",
+				Optional:    true,
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					rotatedBy(path.Root("keepers")),
+				},
+			},
+			"certificate": schema.StringAttribute{
+				Description: "Client certificate of an ssl user, in PEM format.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					rotatedBy(path.Root("keepers")),
+				},
+			},
+			"private_key": schema.StringAttribute{
+				Description: "Private key for the client certificate of an ssl user, in PEM format.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					rotatedBy(path.Root("keepers")),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				Description: "Arbitrary values that rotate the credentials in place when they change. " +
+					"A set password is kept, only generated passwords and certificates are replaced.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+// ValidateConfig rejects a password for ssl users, which use certificates.
+func (r *userResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config userResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if strings.EqualFold(config.Type.ValueString(), "ssl") && !config.Password.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Password for ssl user",
+			"ssl users authenticate with a certificate, remove password or use a sasl user.")
+	}
+}
+
 // Configure adds the provider configured client to the data source.
 func (r *userResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -89,15 +195,27 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	createRequest := api.User{
-		Name: plan.Name.ValueString(),
-		Type: plan.Type.ValueString(),
+		Name:     plan.Name.ValueString(),
+		Type:     plan.Type.ValueString(),
+		Password: plan.Password.ValueString(),
 	}
-	err := r.client.CreateUser(plan.InstanceID.ValueInt64(), createRequest)
+	user, err := r.client.CreateUser(ctx, plan.InstanceID.ValueInt64(), createRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating user", err.Error())
 		return
 	}
+	plan.Type = userType(plan.Type, user.Type)
+	plan.setCredentials(user)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		tflog.Error(ctx, fmt.Sprintf("created diag failed"))
@@ -111,21 +229,58 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	user, err := r.client.ReadUser(state.InstanceID.ValueInt64(), state.Name.ValueString())
+	user, err := r.client.ReadUser(ctx, state.InstanceID.ValueInt64(), state.Name.ValueString())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read user state", err.Error())
 		return
 	}
 	state.Name = types.StringValue(user.Name)
-	state.Type = types.StringValue(user.Type)
+	state.Type = userType(state.Type, user.Type)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
 
-// Update updates the resource and sets the updated Terraform state on success.
+// Update rotates the credentials when the password or keepers changed.
 func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state userResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	passwordChanged := !plan.Password.IsUnknown() && !plan.Password.Equal(state.Password)
+	if passwordChanged || !sameKeepers(plan.Keepers, state.Keepers) {
+		password := ""
+		if !plan.Password.IsUnknown() {
+			password = plan.Password.ValueString()
+		}
+		user, err := r.client.RotateUserCredentials(ctx, plan.InstanceID.ValueInt64(), plan.Name.ValueString(), password)
+		if err != nil {
+			resp.Diagnostics.AddError("Error rotating user credentials", err.Error())
+			return
+		}
+		plan.setCredentials(user)
+	} else {
+		plan.Password, plan.Certificate, plan.PrivateKey = state.Password, state.Certificate, state.PrivateKey
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -136,7 +291,15 @@ func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	err := r.client.DeleteUser(state.InstanceID.ValueInt64(), state.Name.ValueString())
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteUser(ctx, state.InstanceID.ValueInt64(), state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting user", err.Error())
 		return
@@ -146,6 +309,11 @@ func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 }
 
 func (r *userResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	instanceID, name, err := splitImportID(req.ID, "<instance_id>/<user>")
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), instanceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
 }
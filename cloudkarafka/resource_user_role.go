@@ -0,0 +1,558 @@
+package cloudkarafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"terraform-provider-cloudkarafka/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &userRoleResource{}
+	_ resource.ResourceWithConfigure      = &userRoleResource{}
+	_ resource.ResourceWithModifyPlan     = &userRoleResource{}
+	_ resource.ResourceWithValidateConfig = &userRoleResource{}
+)
+
+// clusterPattern is the resource pattern of rules on the cluster.
+const clusterPattern = "kafka-cluster"
+
+// userRoles are the roles a user can be given, see expandRole.
+var userRoles = []string{"producer", "consumer", "admin"}
+
+// NewUserRoleResource is a helper function to simplify the provider implementation.
+func NewUserRoleResource() resource.Resource {
+	return &userRoleResource{}
+}
+
+// userRoleResource is the resource implementation.
+type userRoleResource struct {
+	client *api.API
+}
+
+// The sets are kept as types so that the rules can be planned while they are
+// still unknown.
+type userRoleResourceModel struct {
+	InstanceID       types.Int64    `tfsdk:"instance_id"`
+	User             types.String   `tfsdk:"username"`
+	Role             types.String   `tfsdk:"role"`
+	Topics           types.Set      `tfsdk:"topics"`
+	Groups           types.Set      `tfsdk:"groups"`
+	TransactionalIDs types.Set      `tfsdk:"transactional_ids"`
+	PatternType      types.String   `tfsdk:"pattern_type"`
+	Rules            types.List     `tfsdk:"rules"`
+	Timeouts         timeouts.Value `tfsdk:"timeouts"`
+}
+
+type userRoleRuleModel struct {
+	ID                  types.Int64  `tfsdk:"id"`
+	Operation           types.String `tfsdk:"operation"`
+	Resource            types.String `tfsdk:"resource"`
+	ResourcePattern     types.String `tfsdk:"resource_pattern"`
+	ResourcePatternType types.String `tfsdk:"resource_pattern_type"`
+}
+
+var userRoleRuleType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"id":                    types.Int64Type,
+	"operation":             types.StringType,
+	"resource":              types.StringType,
+	"resource_pattern":      types.StringType,
+	"resource_pattern_type": types.StringType,
+}}
+
+func (m userRoleRuleModel) aclRule() api.AclRule {
+	return api.AclRule{
+		Operation:           m.Operation.ValueString(),
+		Resource:            m.Resource.ValueString(),
+		ResourcePattern:     m.ResourcePattern.ValueString(),
+		ResourcePatternType: m.ResourcePatternType.ValueString(),
+	}
+}
+
+// expandRole returns the ACL rules a role needs on the given topics, groups
+// and transactional IDs:
+//
+//   - producer: write and describe on the topics. With transactional IDs
+//     also write and describe on them, and idempotent_write on the cluster.
+//   - consumer: read and describe on the topics, and read on the groups.
+//   - admin: all on the topics, groups and transactional IDs.
+func expandRole(role string, topics, groups, transactionalIDs []string, patternType string) []api.AclRule {
+	var rules []api.AclRule
+	add := func(resource string, patterns []string, operations ...string) {
+		patterns = append([]string{}, patterns...)
+		sort.Strings(patterns)
+		for _, p := range patterns {
+			for _, op := range operations {
+				rules = append(rules, api.AclRule{
+					Operation:           op,
+					Resource:            resource,
+					ResourcePattern:     p,
+					ResourcePatternType: patternType,
+				})
+			}
+		}
+	}
+	switch role {
+	case "producer":
+		add("topic", topics, "write", "describe")
+		add("transactional_id", transactionalIDs, "write", "describe")
+		if len(transactionalIDs) > 0 {
+			rules = append(rules, api.AclRule{
+				Operation:           "idempotent_write",
+				Resource:            "cluster",
+				ResourcePattern:     clusterPattern,
+				ResourcePatternType: "literal",
+			})
+		}
+	case "consumer":
+		add("topic", topics, "read", "describe")
+		add("group", groups, "read")
+	case "admin":
+		add("topic", topics, "all")
+		add("group", groups, "all")
+		add("transactional_id", transactionalIDs, "all")
+	}
+	return rules
+}
+
+// known reports whether the rules of the role can be worked out.
+func (m *userRoleResourceModel) known() bool {
+	return !m.Role.IsUnknown() && !m.PatternType.IsUnknown() &&
+		!m.Topics.IsUnknown() && !m.Groups.IsUnknown() && !m.TransactionalIDs.IsUnknown()
+}
+
+// expand returns the ACL rules of the role.
+func (m *userRoleResourceModel) expand(ctx context.Context) ([]api.AclRule, diag.Diagnostics) {
+	var (
+		diags                            diag.Diagnostics
+		topics, groups, transactionalIDs []string
+	)
+	diags.Append(m.Topics.ElementsAs(ctx, &topics, false)...)
+	diags.Append(m.Groups.ElementsAs(ctx, &groups, false)...)
+	diags.Append(m.TransactionalIDs.ElementsAs(ctx, &transactionalIDs, false)...)
+	patternType := "literal"
+	if !m.PatternType.IsNull() {
+		patternType = m.PatternType.ValueString()
+	}
+	return expandRole(m.Role.ValueString(), topics, groups, transactionalIDs, patternType), diags
+}
+
+// rules returns the rules in place, as kept in state.
+func (m *userRoleResourceModel) rules(ctx context.Context) ([]userRoleRuleModel, diag.Diagnostics) {
+	var rules []userRoleRuleModel
+	if m.Rules.IsNull() || m.Rules.IsUnknown() {
+		return rules, nil
+	}
+	diags := m.Rules.ElementsAs(ctx, &rules, false)
+	return rules, diags
+}
+
+// setRules stores the rules in place.
+func (m *userRoleResourceModel) setRules(ctx context.Context, rules []userRoleRuleModel) diag.Diagnostics {
+	if rules == nil {
+		rules = []userRoleRuleModel{}
+	}
+	var diags diag.Diagnostics
+	m.Rules, diags = types.ListValueFrom(ctx, userRoleRuleType, rules)
+	return diags
+}
+
+// matchRules pairs the wanted rules with the ones in place. Wanted rules
+// without a match have an unknown ID, rules in place that are not wanted are
+// returned as stale.
+func matchRules(wanted []api.AclRule, current []userRoleRuleModel) (rules, stale []userRoleRuleModel) {
+	used := make([]bool, len(current))
+	for _, w := range wanted {
+		rule := userRoleRuleModel{
+			ID:                  types.Int64Unknown(),
+			Operation:           types.StringValue(w.Operation),
+			Resource:            types.StringValue(w.Resource),
+			ResourcePattern:     types.StringValue(w.ResourcePattern),
+			ResourcePatternType: types.StringValue(w.ResourcePatternType),
+		}
+		for n, c := range current {
+			if cr := c.aclRule(); !used[n] && cr.Same(&w) {
+				rule, used[n] = c, true
+				break
+			}
+		}
+		rules = append(rules, rule)
+	}
+	for n, c := range current {
+		if !used[n] {
+			stale = append(stale, c)
+		}
+	}
+	return rules, stale
+}
+
+// Metadata returns the data source type name.
+func (r *userRoleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_role"
+}
+
+// Schema defines the schema for the data source.
+func (r *userRoleResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Give a user the ACL rules of a role on topics, groups and transactional IDs. " +
+			"A producer gets write and describe on the topics, and with transactional IDs also write and describe on them " +
+			"and idempotent_write on the cluster. A consumer gets read and describe on the topics and read on the groups. " +
+			"An admin gets all on the topics, groups and transactional IDs. " +
+			"The rules are managed as a unit, rules deleted outside Terraform are put back.",
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.Int64Attribute{
+				Description: "Id of the instance where we want to manage the rules.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "Name of the user to give the role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				Description: "The role, producer, consumer or admin.",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.OneOf(userRoles...)},
+			},
+			"topics": schema.SetAttribute{
+				Description: "Topics the role applies to.",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators:  []validator.Set{setvalidator.SizeAtLeast(1)},
+			},
+			"groups": schema.SetAttribute{
+				Description: "Consumer groups the role applies to, required for consumers.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"transactional_ids": schema.SetAttribute{
+				Description: "Transactional IDs of transactional producers.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"pattern_type": schema.StringAttribute{
+				Description: "How to match topics, groups and transactional IDs, literal or prefixed. Defaults to literal.",
+				Optional:    true,
+				Validators:  []validator.String{stringvalidator.OneOf("literal", "prefixed")},
+			},
+			"rules": schema.ListNestedAttribute{
+				Description: "The ACL rules of the role.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Description: "Rule ID.",
+							Computed:    true,
+						},
+						"operation": schema.StringAttribute{
+							Description: "Operation the rule allows.",
+							Computed:    true,
+						},
+						"resource": schema.StringAttribute{
+							Description: "Type of resource the rule applies to.",
+							Computed:    true,
+						},
+						"resource_pattern": schema.StringAttribute{
+							Description: "Which resources the rule applies to.",
+							Computed:    true,
+						},
+						"resource_pattern_type": schema.StringAttribute{
+							Description: "How the resource_pattern is applied, literal or prefixed.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig checks that the role is given what it applies to.
+func (r *userRoleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config userRoleResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Role.IsUnknown() {
+		return
+	}
+	switch config.Role.ValueString() {
+	case "producer":
+		if !config.Groups.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("groups"), "Groups for producer",
+				"Producers do not consume, remove groups or use the consumer role.")
+		}
+	case "consumer":
+		if config.Groups.IsNull() || (!config.Groups.IsUnknown() && len(config.Groups.Elements()) == 0) {
+			resp.Diagnostics.AddAttributeError(path.Root("groups"), "Missing groups",
+				"Consumers need groups to read with.")
+		}
+		if !config.TransactionalIDs.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("transactional_ids"), "Transactional IDs for consumer",
+				"Only producers write transactionally, remove transactional_ids or use the producer role.")
+		}
+	}
+}
+
+// ModifyPlan plans the rules of the role, keeping the IDs of the rules that
+// are already in place.
+func (r *userRoleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan userRoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.known() {
+		return
+	}
+
+	var current []userRoleRuleModel
+	if !req.State.Raw.IsNull() {
+		var state userRoleResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// A replaced role starts over.
+		if state.InstanceID.Equal(plan.InstanceID) && state.User.Equal(plan.User) {
+			rules, diags := state.rules(ctx)
+			resp.Diagnostics.Append(diags...)
+			current = rules
+		}
+	}
+	wanted, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	rules, _ := matchRules(wanted, current)
+	resp.Diagnostics.Append(plan.setRules(ctx, rules)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("rules"), plan.Rules)...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *userRoleResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.API)
+}
+
+// apply creates the rules of the role that are missing from current and
+// deletes the ones the role no longer needs. It returns the rules in place,
+// also when it fails halfway.
+func (r *userRoleResource) apply(ctx context.Context, plan *userRoleResourceModel, current []userRoleRuleModel) ([]userRoleRuleModel, error) {
+	wanted, diags := plan.expand(ctx)
+	if diags.HasError() {
+		return current, fmt.Errorf("reading role settings: %v", diags)
+	}
+	rules, stale := matchRules(wanted, current)
+
+	var (
+		instanceID = plan.InstanceID.ValueInt64()
+		user       = plan.User.ValueString()
+		applied    []userRoleRuleModel
+	)
+	for _, rule := range rules {
+		if !rule.ID.IsUnknown() {
+			applied = append(applied, rule)
+		}
+	}
+	for n, rule := range stale {
+		err := r.client.DeleteAclRule(ctx, instanceID, rule.ID.ValueInt64())
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			return append(applied, stale[n:]...), err
+		}
+	}
+	for n, rule := range rules {
+		if !rule.ID.IsUnknown() {
+			continue
+		}
+		id, err := r.client.CreateAclRule(ctx, instanceID, user, rule.aclRule())
+		if err != nil {
+			return applied, err
+		}
+		rules[n].ID = types.Int64Value(id)
+		applied = append(applied, rules[n])
+	}
+	return rules, nil
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *userRoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan userRoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	rules, err := r.apply(ctx, &plan, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating rules", err.Error())
+		if len(rules) == 0 {
+			return
+		}
+		// Keep the rules that were created, so they are cleaned up.
+	}
+	resp.Diagnostics.Append(plan.setRules(ctx, rules)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data. Rules recreated
+// outside Terraform are adopted under their new id, rules deleted outside
+// Terraform are dropped, so the next plan puts them back.
+func (r *userRoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state userRoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	existing, err := r.client.ListAclRules(ctx, state.InstanceID.ValueInt64(), state.User.ValueString())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error refreshing rules", err.Error())
+		return
+	}
+	current, diags := state.rules(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ids := make(map[int64]bool)
+	for _, rule := range existing {
+		ids[rule.Id] = true
+	}
+	// Rules still under their id are not adopted again.
+	claimed := make(map[int64]bool)
+	for _, rule := range current {
+		claimed[rule.ID.ValueInt64()] = ids[rule.ID.ValueInt64()]
+	}
+	var rules []userRoleRuleModel
+	for _, rule := range current {
+		if !ids[rule.ID.ValueInt64()] {
+			want := rule.aclRule()
+			want.User = state.User.ValueString()
+			for _, e := range existing {
+				if !claimed[e.Id] && e.Same(&want) {
+					tflog.Info(ctx, fmt.Sprintf("ACL rule %d not found, using rule %d with the same settings",
+						rule.ID.ValueInt64(), e.Id))
+					rule.ID = types.Int64Value(e.Id)
+					claimed[e.Id] = true
+					break
+				}
+			}
+		}
+		if ids[rule.ID.ValueInt64()] {
+			rules = append(rules, rule)
+		}
+	}
+	resp.Diagnostics.Append(state.setRules(ctx, rules)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update creates and deletes rules to match the planned role.
+func (r *userRoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state userRoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	current, diags := state.rules(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	rules, err := r.apply(ctx, &plan, current)
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating rules", err.Error())
+		// Record what is in place, the rest is retried on the next apply.
+		resp.Diagnostics.Append(state.setRules(ctx, rules)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+	resp.Diagnostics.Append(plan.setRules(ctx, rules)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *userRoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state userRoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	rules, diags := state.rules(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, rule := range rules {
+		err := r.client.DeleteAclRule(ctx, state.InstanceID.ValueInt64(), rule.ID.ValueInt64())
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			resp.Diagnostics.AddError("Error deleting rule", err.Error())
+			return
+		}
+	}
+}
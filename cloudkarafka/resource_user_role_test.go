@@ -0,0 +1,223 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestExpandRole(t *testing.T) {
+	rule := func(op, resource, pattern, patternType string) api.AclRule {
+		return api.AclRule{Operation: op, Resource: resource, ResourcePattern: pattern, ResourcePatternType: patternType}
+	}
+	tests := []struct {
+		name string
+		role string
+		tx   []string
+		want []api.AclRule
+	}{
+		{
+			name: "producer",
+			role: "producer",
+			want: []api.AclRule{
+				rule("write", "topic", "a", "prefixed"),
+				rule("describe", "topic", "a", "prefixed"),
+				rule("write", "topic", "b", "prefixed"),
+				rule("describe", "topic", "b", "prefixed"),
+			},
+		},
+		{
+			name: "transactional producer",
+			role: "producer",
+			tx:   []string{"tx"},
+			want: []api.AclRule{
+				rule("write", "topic", "a", "prefixed"),
+				rule("describe", "topic", "a", "prefixed"),
+				rule("write", "topic", "b", "prefixed"),
+				rule("describe", "topic", "b", "prefixed"),
+				rule("write", "transactional_id", "tx", "prefixed"),
+				rule("describe", "transactional_id", "tx", "prefixed"),
+				rule("idempotent_write", "cluster", "kafka-cluster", "literal"),
+			},
+		},
+		{
+			name: "consumer",
+			role: "consumer",
+			want: []api.AclRule{
+				rule("read", "topic", "a", "prefixed"),
+				rule("describe", "topic", "a", "prefixed"),
+				rule("read", "topic", "b", "prefixed"),
+				rule("describe", "topic", "b", "prefixed"),
+				rule("read", "group", "g", "prefixed"),
+			},
+		},
+		{
+			name: "admin",
+			role: "admin",
+			tx:   []string{"tx"},
+			want: []api.AclRule{
+				rule("all", "topic", "a", "prefixed"),
+				rule("all", "topic", "b", "prefixed"),
+				rule("all", "group", "g", "prefixed"),
+				rule("all", "transactional_id", "tx", "prefixed"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var groups []string
+			if tt.role != "producer" {
+				groups = []string{"g"}
+			}
+			got := expandRole(tt.role, []string{"b", "a"}, groups, tt.tx, "prefixed")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandRole() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccUserRoleResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddInstance(api.InstanceResponse{Name: "test"})
+	srv.AddAclRule(id, api.AclRule{
+		User:                "alice",
+		Operation:           "alter",
+		Resource:            "cluster",
+		ResourcePattern:     "kafka-cluster",
+		ResourcePatternType: "literal",
+	})
+
+	producer := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_user_role" "test" {
+  instance_id       = %d
+  username          = "alice"
+  role              = "producer"
+  topics            = ["orders"]
+  transactional_ids = ["orders-tx"]
+}
+`, id)
+	consumer := testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_user_role" "test" {
+  instance_id = %d
+  username    = "alice"
+  role        = "consumer"
+  topics      = ["orders"]
+  groups      = ["billing"]
+}
+`, id)
+	// checkServer checks how many rules alice has on the server, besides
+	// the one added before the test.
+	checkServer := func(want int) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			var n int
+			for _, r := range srv.AclRules(id) {
+				if r.User == "alice" && r.Operation != "alter" {
+					n++
+				}
+			}
+			if n != want || len(srv.AclRules(id)) != want+1 {
+				return fmt.Errorf("server has %d rules for the role, want %d: %+v", n, want, srv.AclRules(id))
+			}
+			return nil
+		}
+	}
+	var describeID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkServer(0),
+		Steps: []resource.TestStep{
+			{
+				Config: producer,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_user_role.test", "rules.#", "5"),
+					resource.TestCheckResourceAttr("cloudkarafka_user_role.test", "rules.1.operation", "describe"),
+					resource.TestCheckResourceAttr("cloudkarafka_user_role.test", "rules.4.operation", "idempotent_write"),
+					resource.TestCheckResourceAttrWith("cloudkarafka_user_role.test", "rules.1.id", func(v string) error {
+						describeID = v
+						return nil
+					}),
+					checkServer(5),
+				),
+			},
+			{
+				// A rule deleted outside Terraform is put back.
+				PreConfig: func() {
+					for _, r := range srv.AclRules(id) {
+						if r.Resource == "transactional_id" && r.Operation == "write" {
+							srv.DeleteAclRule(id, r.Id)
+						}
+					}
+				},
+				Config:             producer,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: producer,
+				Check:  checkServer(5),
+			},
+			{
+				// The same rule under a new id is adopted, not recreated.
+				PreConfig: func() {
+					for _, r := range srv.AclRules(id) {
+						if fmt.Sprint(r.Id) == describeID {
+							srv.DeleteAclRule(id, r.Id)
+							describeID = fmt.Sprint(srv.AddAclRule(id, r))
+						}
+					}
+				},
+				Config: producer,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("cloudkarafka_user_role.test", "rules.1.id", func(v string) error {
+						if v != describeID {
+							return fmt.Errorf("describe rule has id %s, want the new id %s", v, describeID)
+						}
+						return nil
+					}),
+					checkServer(5),
+				),
+			},
+			{
+				// The describe rule on the topic is kept when the role changes.
+				Config: consumer,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_user_role.test", "rules.#", "3"),
+					resource.TestCheckResourceAttrWith("cloudkarafka_user_role.test", "rules.1.id", func(v string) error {
+						if v != describeID {
+							return fmt.Errorf("describe rule was recreated, id %s, was %s", v, describeID)
+						}
+						return nil
+					}),
+					checkServer(3),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserRoleResource_validation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_user_role" "test" {
+  instance_id = 1
+  username    = "alice"
+  role        = "consumer"
+  topics      = ["orders"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Consumers need groups`),
+			},
+		},
+	})
+}
@@ -0,0 +1,259 @@
+package cloudkarafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"terraform-provider-cloudkarafka/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &vpcPeeringResource{}
+	_ resource.ResourceWithConfigure   = &vpcPeeringResource{}
+	_ resource.ResourceWithImportState = &vpcPeeringResource{}
+)
+
+// NewVPCPeeringResource is a helper function to simplify the provider implementation.
+func NewVPCPeeringResource() resource.Resource {
+	return &vpcPeeringResource{}
+}
+
+// vpcPeeringResource is the resource implementation.
+type vpcPeeringResource struct {
+	client *api.API
+}
+
+type vpcPeeringResourceModel struct {
+	VPCID         types.Int64    `tfsdk:"vpc_id"`
+	PeeringID     types.String   `tfsdk:"peering_id"`
+	PeerNetworkID types.String   `tfsdk:"peer_network_id"`
+	PeerAccountID types.String   `tfsdk:"peer_account_id"`
+	PeerRegion    types.String   `tfsdk:"peer_region"`
+	PeerSubnet    types.String   `tfsdk:"peer_subnet"`
+	Status        types.String   `tfsdk:"status"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// setPeering copies the peering as reported by the API into the model.
+// Optional settings are only refreshed when set.
+func (m *vpcPeeringResourceModel) setPeering(p api.VPCPeering) {
+	m.PeeringID = types.StringValue(p.Id)
+	m.PeerNetworkID = types.StringValue(p.PeerNetworkId)
+	if !m.PeerAccountID.IsNull() || p.PeerAccountId != "" {
+		m.PeerAccountID = types.StringValue(p.PeerAccountId)
+	}
+	if !m.PeerRegion.IsNull() || p.PeerRegion != "" {
+		m.PeerRegion = types.StringValue(p.PeerRegion)
+	}
+	if !m.PeerSubnet.IsNull() || p.PeerSubnet != "" {
+		m.PeerSubnet = types.StringValue(p.PeerSubnet)
+	}
+	m.Status = types.StringValue(p.Status)
+}
+
+// Metadata returns the data source type name.
+func (r *vpcPeeringResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vpc_peering"
+}
+
+// Schema defines the schema for the data source.
+func (r *vpcPeeringResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage a peering between a VPC and a network in AWS, GCP or Azure. " +
+			"Without peering_id the peering is requested from CloudKarafka, for AWS the request must then be accepted on the AWS side. " +
+			"With peering_id a request made from the peer side is accepted. " +
+			"See the `cloudkarafka_vpc_peering_info` data source for what the peer side needs.",
+		Attributes: map[string]schema.Attribute{
+			"vpc_id": schema.Int64Attribute{
+				Description: "ID of the CloudKarafka VPC.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"peering_id": schema.StringAttribute{
+				Description: "ID of the peering. Set it to accept a peering request made from the peer side.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"peer_network_id": schema.StringAttribute{
+				Description: "The network to peer with: an AWS VPC ID, a GCP network URI or an Azure virtual network resource ID.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"peer_account_id": schema.StringAttribute{
+				Description: "AWS account, GCP project or Azure subscription of the peer network.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"peer_region": schema.StringAttribute{
+				Description: "Region of the peer network, for AWS peerings across regions.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"peer_subnet": schema.StringAttribute{
+				Description: "Subnet of the peer network to route to, in CIDR notation.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of the peering: pending, pending-acceptance, active or failed.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *vpcPeeringResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.API)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *vpcPeeringResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan vpcPeeringResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, 30*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	var (
+		peering api.VPCPeering
+		err     error
+	)
+	if plan.PeeringID.ValueString() != "" {
+		peering, err = r.client.AcceptVPCPeering(ctx, plan.VPCID.ValueInt64(), plan.PeeringID.ValueString())
+	} else {
+		peering, err = r.client.RequestVPCPeering(ctx, plan.VPCID.ValueInt64(), api.VPCPeering{
+			PeerNetworkId: plan.PeerNetworkID.ValueString(),
+			PeerAccountId: plan.PeerAccountID.ValueString(),
+			PeerRegion:    plan.PeerRegion.ValueString(),
+			PeerSubnet:    plan.PeerSubnet.ValueString(),
+		})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating VPC peering", err.Error())
+		if peering.Id == "" {
+			return
+		}
+		// A failed peering still exists, keep it so it is replaced.
+	}
+
+	plan.PeeringID = types.StringValue(peering.Id)
+	plan.Status = types.StringValue(peering.Status)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *vpcPeeringResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state vpcPeeringResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	peering, err := r.client.ReadVPCPeering(ctx, state.VPCID.ValueInt64(), state.PeeringID.ValueString())
+	if errors.Is(err, api.ErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing from state: %s", err))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read VPC peering state", err.Error())
+		return
+	}
+	state.setPeering(peering)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update only stores changed timeouts, every other change replaces the peering.
+func (r *vpcPeeringResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan vpcPeeringResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *vpcPeeringResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state vpcPeeringResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.DeleteVPCPeering(ctx, state.VPCID.ValueInt64(), state.PeeringID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting VPC peering", err.Error())
+	}
+}
+
+func (r *vpcPeeringResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	vpc, peeringID, ok := strings.Cut(req.ID, "/")
+	vpcID, err := strconv.ParseInt(vpc, 10, 64)
+	if !ok || peeringID == "" || err != nil {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("expected import ID in the format <vpc_id>/<peering_id>, got %q", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vpc_id"), vpcID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("peering_id"), peeringID)...)
+}
@@ -0,0 +1,112 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccVPCPeeringResource_request(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddVPC(api.VPCResponse{Name: "network", Region: "google-compute-engine::europe-west1", Subnet: "10.56.72.0/24"})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := srv.VPCPeering(id, fmt.Sprintf("pcx-%d-1", id)); ok {
+				return fmt.Errorf("peering still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+data "cloudkarafka_vpc_peering_info" "test" {
+  vpc_id = %d
+}
+
+resource "cloudkarafka_vpc_peering" "test" {
+  vpc_id          = %d
+  peer_network_id = "projects/example/global/networks/default"
+}
+`, id, id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cloudkarafka_vpc_peering_info.test", "subnet", "10.56.72.0/24"),
+					resource.TestCheckResourceAttr("data.cloudkarafka_vpc_peering_info.test", "network_id", fmt.Sprintf("projects/cloudkarafka/global/networks/vpc-%d", id)),
+					resource.TestCheckResourceAttr("cloudkarafka_vpc_peering.test", "peering_id", fmt.Sprintf("pcx-%d-1", id)),
+					resource.TestCheckResourceAttr("cloudkarafka_vpc_peering.test", "status", "active"),
+					resource.TestCheckNoResourceAttr("cloudkarafka_vpc_peering.test", "peer_subnet"),
+				),
+			},
+			{
+				ResourceName:                         "cloudkarafka_vpc_peering.test",
+				ImportState:                          true,
+				ImportStateId:                        fmt.Sprintf("%d/pcx-%d-1", id, id),
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "peering_id",
+			},
+			{
+				ResourceName:  "cloudkarafka_vpc_peering.test",
+				ImportState:   true,
+				ImportStateId: "pcx-1-1",
+				ExpectError:   regexp.MustCompile(`format <vpc_id>/<peering_id>`),
+			},
+			{
+				// Changing only the timeouts keeps the peering.
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_vpc_peering" "test" {
+  vpc_id          = %d
+  peer_network_id = "projects/example/global/networks/default"
+
+  timeouts {
+    delete = "20m"
+  }
+}
+`, id),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_vpc_peering.test", "peering_id", fmt.Sprintf("pcx-%d-1", id)),
+					resource.TestCheckResourceAttr("cloudkarafka_vpc_peering.test", "timeouts.delete", "20m"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVPCPeeringResource_accept(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddVPC(api.VPCResponse{Name: "network", Region: "amazon-web-services::us-east-1", Subnet: "10.56.72.0/24"})
+	peeringID := srv.AddVPCPeeringRequest(id, api.VPCPeering{
+		PeerNetworkId: "vpc-0123abcd",
+		PeerAccountId: "210987654321",
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + fmt.Sprintf(`
+resource "cloudkarafka_vpc_peering" "test" {
+  vpc_id          = %d
+  peering_id      = %q
+  peer_network_id = "vpc-0123abcd"
+  peer_account_id = "210987654321"
+}
+`, id, peeringID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_vpc_peering.test", "status", "active"),
+					func(*terraform.State) error {
+						if p, _ := srv.VPCPeering(id, peeringID); p.Status != api.VPCPeeringActive {
+							return fmt.Errorf("peering %s is %s, want active", peeringID, p.Status)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
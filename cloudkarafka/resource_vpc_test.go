@@ -0,0 +1,118 @@
+package cloudkarafka
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-cloudkarafka/api"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccVPCResource_import(t *testing.T) {
+	srv := testAccServer(t)
+	id := srv.AddVPC(api.VPCResponse{
+		Name:   "network",
+		Region: "amazon-web-services::us-east-1",
+		Subnet: "10.56.72.0/24",
+		Tags:   []string{"production"},
+	})
+
+	config := testAccProviderConfig + `
+resource "cloudkarafka_vpc" "test" {
+  name   = "network"
+  region = "amazon-web-services::us-east-1"
+  subnet = "10.56.72.0/24"
+  tags   = ["production"]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: append([]resource.TestStep{
+			{
+				Config:        config,
+				ResourceName:  "cloudkarafka_vpc.test",
+				ImportState:   true,
+				ImportStateId: "network",
+				ExpectError:   regexp.MustCompile(`Expected a numeric VPC ID`),
+			},
+		}, importSteps("cloudkarafka_vpc.test", fmt.Sprint(id), config, map[string]string{
+			"id":     fmt.Sprint(id),
+			"name":   "network",
+			"region": "amazon-web-services::us-east-1",
+			"subnet": "10.56.72.0/24",
+			"tags.#": "1",
+			"tags.0": "production",
+		})...),
+	})
+}
+
+func TestAccVPCResource_basic(t *testing.T) {
+	srv := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := srv.VPC(1); ok {
+				return fmt.Errorf("VPC 1 still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_vpc" "test" {
+  name   = "network"
+  region = "amazon-web-services::us-east-1"
+  subnet = "10.56.72.0/24"
+}
+
+resource "cloudkarafka_instance" "test" {
+  name   = "test"
+  plan   = "ducky"
+  region = cloudkarafka_vpc.test.region
+  vpc_id = cloudkarafka_vpc.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_vpc.test", "id", "1"),
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "vpc_id", "1"),
+					resource.TestCheckResourceAttr("cloudkarafka_instance.test", "vpc_subnet", "10.56.72.0/24"),
+				),
+			},
+			{
+				ResourceName:      "cloudkarafka_vpc.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Changing only the timeouts keeps the VPC.
+				Config: testAccProviderConfig + `
+resource "cloudkarafka_vpc" "test" {
+  name   = "network"
+  region = "amazon-web-services::us-east-1"
+  subnet = "10.56.72.0/24"
+
+  timeouts {
+    delete = "20m"
+  }
+}
+
+resource "cloudkarafka_instance" "test" {
+  name   = "test"
+  plan   = "ducky"
+  region = cloudkarafka_vpc.test.region
+  vpc_id = cloudkarafka_vpc.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cloudkarafka_vpc.test", "id", "1"),
+					resource.TestCheckResourceAttr("cloudkarafka_vpc.test", "timeouts.delete", "20m"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,9 @@
+# Look up a shared cluster by name.
+data "cloudkarafka_instance" "shared" {
+  name = "shared-cluster"
+}
+
+# Or by id.
+data "cloudkarafka_instance" "by_id" {
+  id = 1234
+}
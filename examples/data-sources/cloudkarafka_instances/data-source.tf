@@ -0,0 +1,4 @@
+# List all instances tagged with production.
+data "cloudkarafka_instances" "production" {
+  tags = ["production"]
+}
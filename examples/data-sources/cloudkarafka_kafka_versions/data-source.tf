@@ -0,0 +1,9 @@
+# Run the newest Kafka version the service supports.
+data "cloudkarafka_kafka_versions" "available" {}
+
+resource "cloudkarafka_instance" "example" {
+  name          = "example"
+  plan          = "dedicated_2-1"
+  region        = "amazon-web-services::us-east-1"
+  kafka_version = data.cloudkarafka_kafka_versions.available.latest
+}
@@ -0,0 +1,8 @@
+data "cloudkarafka_schema_registry_subject" "orders" {
+  instance_id = data.cloudkarafka_instance.shared.id
+  subject     = "orders-value"
+}
+
+output "orders_schema_id" {
+  value = data.cloudkarafka_schema_registry_subject.orders.schema_id
+}
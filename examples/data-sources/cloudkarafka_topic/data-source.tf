@@ -0,0 +1,4 @@
+data "cloudkarafka_topic" "orders" {
+  instance_id = data.cloudkarafka_instance.shared.id
+  name        = "orders"
+}
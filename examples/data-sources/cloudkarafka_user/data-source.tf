@@ -0,0 +1,4 @@
+data "cloudkarafka_user" "app" {
+  instance_id = data.cloudkarafka_instance.shared.id
+  name        = "app"
+}
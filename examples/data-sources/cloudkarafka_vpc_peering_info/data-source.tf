@@ -0,0 +1,8 @@
+# What the AWS side needs to request a peering.
+data "cloudkarafka_vpc_peering_info" "network" {
+  vpc_id = cloudkarafka_vpc.network.id
+}
+
+output "cloudkarafka_vpc" {
+  value = data.cloudkarafka_vpc_peering_info.network.network_id
+}
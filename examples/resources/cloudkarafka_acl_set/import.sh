@@ -0,0 +1,2 @@
+# The ACL rules of a user can be imported by specifying the instance identifier and the username.
+terraform import cloudkarafka_acl_set.myuser 123/user1
@@ -0,0 +1,19 @@
+# All ACL rules of the user, rules not listed here are deleted.
+resource "cloudkarafka_acl_set" "myuser" {
+  instance_id = cloudkarafka_instance.cluster.id
+  username    = cloudkarafka_user.myuser.name
+
+  rules {
+    operation             = "read"
+    resource              = "topic"
+    resource_pattern      = "orders."
+    resource_pattern_type = "prefixed"
+  }
+
+  rules {
+    operation             = "read"
+    resource              = "group"
+    resource_pattern      = "order-service"
+    resource_pattern_type = "literal"
+  }
+}
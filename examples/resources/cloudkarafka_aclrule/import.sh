@@ -0,0 +1,8 @@
+# An ACL rule can be imported by specifying the instance identifier and the rule identifier.
+terraform import cloudkarafka_aclrule.example 123/42
+
+# Or by specifying the instance identifier and the rule itself, as
+# <username>:<resource>:<resource_pattern>:<resource_pattern_type>:<operation>,
+# optionally followed by :<permission_type> and :<host>. Rules with a colon in
+# the resource_pattern can only be imported by their identifier.
+terraform import cloudkarafka_aclrule.example 123/user1:topic:sample-:prefixed:read
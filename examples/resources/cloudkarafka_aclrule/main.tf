@@ -6,3 +6,16 @@ resource "cloudkarafka_aclrule" "myuser_rule1" {
   resource_pattern = "sample-"
   resource_pattern_type = "prefixed"
 }
+
+# Keep the user from writing to the audit topic, even though the rule above
+# would allow it, and only from the given host.
+resource "cloudkarafka_aclrule" "myuser_rule2" {
+  instance_id           = cloudkarafka_instance.cluster.id
+  username              = cloudkarafka_user.myuser.name
+  operation             = "write"
+  resource              = "topic"
+  resource_pattern      = "sample-audit"
+  resource_pattern_type = "literal"
+  permission_type       = "deny"
+  host                  = "10.0.0.1"
+}
@@ -1,2 +1,2 @@
-# Order can be imported by specifying the numeric identifier.
-terraform import cloudkarafka_instance.id123
+# An instance can be imported by specifying its numeric identifier.
+terraform import cloudkarafka_instance.example 123
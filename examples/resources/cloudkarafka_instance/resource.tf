@@ -6,4 +6,11 @@ resource "cloudkarafka_instance" "cluster" {
   kafka_version = "3.3.1"
   disk_size = 128
   tags = ["terraform", "testing"]
+
+  # Set to false and apply before destroying.
+  deletion_protection = true
+
+  timeouts {
+    create = "45m"
+  }
 }
@@ -0,0 +1,2 @@
+# The Kafka config can be imported by specifying the instance identifier.
+terraform import cloudkarafka_kafkaconfig.example 123
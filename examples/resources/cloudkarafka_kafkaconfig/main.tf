@@ -2,5 +2,7 @@ resource "cloudkarafka_kafkaconfig" "kafkaconfig" {
   instance_id = cloudkarafka_instance.cluster.id
   auto_create_topics_enable = true
   num_io_threads = 10
+  properties = {
+    "log.cleaner.threads" = "2"
+  }
 }
-
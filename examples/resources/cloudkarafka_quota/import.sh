@@ -0,0 +1,4 @@
+# A quota can be imported by specifying the instance identifier, the user and the client ID,
+# leaving out the one the quota does not apply to.
+terraform import cloudkarafka_quota.importer 123/importer/
+terraform import cloudkarafka_quota.default_client '123//<default>'
@@ -0,0 +1,14 @@
+# Keep the batch importer from starving the other producers.
+resource "cloudkarafka_quota" "importer" {
+  instance_id        = cloudkarafka_instance.cluster.id
+  user               = cloudkarafka_user.importer.name
+  producer_byte_rate = 5242880
+  request_percentage = 50
+}
+
+# Default limit for clients that do not set a client ID of their own.
+resource "cloudkarafka_quota" "default_client" {
+  instance_id        = cloudkarafka_instance.cluster.id
+  client_id          = "<default>"
+  consumer_byte_rate = 10485760
+}
@@ -0,0 +1,4 @@
+# The global level can be imported by specifying the instance identifier,
+# the level of a subject by also specifying the subject name.
+terraform import cloudkarafka_schema_registry_compatibility.global 123
+terraform import cloudkarafka_schema_registry_compatibility.payments 123/payments-value
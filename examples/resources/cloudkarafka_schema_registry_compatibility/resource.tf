@@ -0,0 +1,11 @@
+resource "cloudkarafka_schema_registry_compatibility" "global" {
+  instance_id = cloudkarafka_instance.cluster.id
+  level       = "FULL_TRANSITIVE"
+}
+
+# Payments may change freely while the service is in development.
+resource "cloudkarafka_schema_registry_compatibility" "payments" {
+  instance_id = cloudkarafka_instance.cluster.id
+  subject     = cloudkarafka_schema_registry_subject.payments.subject
+  level       = "NONE"
+}
@@ -0,0 +1,2 @@
+# A subject can be imported by specifying the instance identifier and the subject name.
+terraform import cloudkarafka_schema_registry_subject.order_value 123/orders-value
@@ -0,0 +1,34 @@
+resource "cloudkarafka_schema_registry_subject" "order_key" {
+  instance_id = cloudkarafka_instance.cluster.id
+  subject     = "orders-key"
+  schema      = file("${path.module}/schemas/order_key.avsc")
+}
+
+# The value schema uses the OrderKey record registered above.
+resource "cloudkarafka_schema_registry_subject" "order_value" {
+  instance_id = cloudkarafka_instance.cluster.id
+  subject     = "orders-value"
+  schema      = file("${path.module}/schemas/order.avsc")
+
+  references {
+    name    = "com.example.OrderKey"
+    subject = cloudkarafka_schema_registry_subject.order_key.subject
+    version = cloudkarafka_schema_registry_subject.order_key.version
+  }
+}
+
+resource "cloudkarafka_schema_registry_subject" "payments" {
+  instance_id = cloudkarafka_instance.cluster.id
+  subject     = "payments-value"
+  schema_type = "PROTOBUF"
+  schema      = <<-EOT
+    syntax = "proto3";
+    package com.example;
+
+    message Payment {
+      string id = 1;
+      int64 amount = 2;
+    }
+  EOT
+  hard_delete = true
+}
@@ -0,0 +1,2 @@
+# The firewall can be imported by specifying the instance identifier.
+terraform import cloudkarafka_security_firewall.cluster 123
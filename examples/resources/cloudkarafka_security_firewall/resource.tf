@@ -0,0 +1,16 @@
+# Only allow the NAT gateways to reach the brokers.
+resource "cloudkarafka_security_firewall" "cluster" {
+  instance_id = cloudkarafka_instance.cluster.id
+
+  rules {
+    ip          = "203.0.113.7/32"
+    services    = ["KAFKA", "SCHEMA_REGISTRY"]
+    description = "NAT gateway a"
+  }
+
+  rules {
+    ip          = "203.0.113.8/32"
+    services    = ["KAFKA", "SCHEMA_REGISTRY"]
+    description = "NAT gateway b"
+  }
+}
@@ -0,0 +1,2 @@
+# A topic can be imported by specifying the instance identifier and the topic name.
+terraform import cloudkarafka_topic.example 123/orders
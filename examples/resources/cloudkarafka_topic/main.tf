@@ -8,4 +8,8 @@ resource "cloudkarafka_topic" "my_topic1" {
 	  retention_bytes = 104857600,
 	  cleanup_policy  = "delete"
   }
+  extra_config = {
+    "compression.type"   = "zstd"
+    "max.message.bytes"  = "2097152"
+  }
 }
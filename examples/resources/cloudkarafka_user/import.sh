@@ -0,0 +1,2 @@
+# A user can be imported by specifying the instance identifier and the user name.
+terraform import cloudkarafka_user.example 123/alice
@@ -2,4 +2,14 @@ resource "cloudkarafka_user" "myuser" {
   instance_id = cloudkarafka_instance.cluster.id
   name        = "user1"
   type        = "sasl"
+
+  # Change a value to generate a new password
+  keepers = {
+    rotated = "2024-01"
+  }
+}
+
+output "myuser_password" {
+  value     = cloudkarafka_user.myuser.password
+  sensitive = true
 }
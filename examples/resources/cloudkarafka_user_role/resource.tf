@@ -0,0 +1,19 @@
+# Let the order service produce to all orders topics, transactionally.
+resource "cloudkarafka_user_role" "order_service" {
+  instance_id       = cloudkarafka_instance.cluster.id
+  username          = cloudkarafka_user.order_service.name
+  role              = "producer"
+  topics            = ["orders."]
+  transactional_ids = ["order-service-"]
+  pattern_type      = "prefixed"
+}
+
+# Let the billing service consume the orders topics in its own group.
+resource "cloudkarafka_user_role" "billing" {
+  instance_id  = cloudkarafka_instance.cluster.id
+  username     = cloudkarafka_user.billing.name
+  role         = "consumer"
+  topics       = ["orders."]
+  groups       = ["billing"]
+  pattern_type = "prefixed"
+}
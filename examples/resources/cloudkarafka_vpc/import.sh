@@ -0,0 +1,2 @@
+# A VPC can be imported by specifying the VPC identifier.
+terraform import cloudkarafka_vpc.network 42
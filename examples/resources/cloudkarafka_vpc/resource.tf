@@ -0,0 +1,15 @@
+# A VPC shared by several instances.
+resource "cloudkarafka_vpc" "network" {
+  name   = "production"
+  region = "amazon-web-services::us-east-1"
+  subnet = "10.56.72.0/24"
+  tags   = ["production"]
+}
+
+resource "cloudkarafka_instance" "cluster" {
+  name     = "production"
+  plan     = "dedicated_2-1"
+  region   = cloudkarafka_vpc.network.region
+  vpc_id   = cloudkarafka_vpc.network.id
+  keep_vpc = true
+}
@@ -0,0 +1,2 @@
+# A peering can be imported by specifying the VPC identifier and the peering identifier.
+terraform import cloudkarafka_vpc_peering.aws 42/pcx-0123456789abcdef0
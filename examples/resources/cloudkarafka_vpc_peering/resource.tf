@@ -0,0 +1,22 @@
+# Peer with a GCP network, which becomes active once both sides are peered.
+resource "cloudkarafka_vpc_peering" "gcp" {
+  vpc_id          = cloudkarafka_vpc.network.id
+  peer_network_id = "projects/my-project/global/networks/default"
+}
+
+# Accept a peering request made from an AWS VPC.
+data "cloudkarafka_vpc_peering_info" "network" {
+  vpc_id = cloudkarafka_vpc.network.id
+}
+
+resource "aws_vpc_peering_connection" "cloudkarafka" {
+  vpc_id        = aws_vpc.main.id
+  peer_vpc_id   = data.cloudkarafka_vpc_peering_info.network.network_id
+  peer_owner_id = data.cloudkarafka_vpc_peering_info.network.account_id
+}
+
+resource "cloudkarafka_vpc_peering" "aws" {
+  vpc_id          = cloudkarafka_vpc.network.id
+  peering_id      = aws_vpc_peering_connection.cloudkarafka.id
+  peer_network_id = aws_vpc.main.id
+}